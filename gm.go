@@ -35,8 +35,19 @@ func main() {
 	mavenBuild := args.HasGumFlag("gm")
 	jbangBuild := args.HasGumFlag("gj")
 	antBuild := args.HasGumFlag("ga")
+	sbtBuild := args.HasGumFlag("gs")
+	millBuild := args.HasGumFlag("gi")
+	bazelBuild := args.HasGumFlag("gz")
+	clojureBuild := args.HasGumFlag("gl")
+	scriptBuild := args.HasGumFlag("gx")
+	amperBuild := args.HasGumFlag("gp")
 	version := args.HasGumFlag("gv")
 	help := args.HasGumFlag("gh")
+	allow := args.HasGumFlag("g-allow")
+	deny := args.HasGumFlag("g-deny")
+	wrapper := args.HasGumFlag("gw")
+	list := args.HasGumFlag("gt")
+	fanOut := args.HasGumFlag("gf")
 
 	if version {
 		fmt.Println("------------------------------------------------------------")
@@ -54,17 +65,55 @@ func main() {
 		fmt.Println("  -gb\tforce Bach build")
 		fmt.Println("  -gc\tdisplays current configuration and quits")
 		fmt.Println("  -gd\tdisplays debug information")
+		fmt.Println("  -gf\truns the build at every Gradle, Maven, and Ant project below the current directory")
 		fmt.Println("  -gg\tforce Gradle build")
 		fmt.Println("  -gh\tdisplays help information")
+		fmt.Println("  -gi\tforce Mill build")
 		fmt.Println("  -gj\tforce JBang execution")
+		fmt.Println("  -gl\tforce Leiningen/Clojure CLI build")
 		fmt.Println("  -gm\tforce Maven build")
 		fmt.Println("  -gn\texecutes nearest build file")
+		fmt.Println("  -go\tscopes Maven builds to the current module (-pl/-am)")
+		fmt.Println("  -gp\tforce Amper build")
 		fmt.Println("  -gq\trun gm in quiet mode")
 		fmt.Println("  -gr\tdo not replace goals/tasks")
+		fmt.Println("  -gs\tforce sbt build")
+		fmt.Println("  -gt\tlists the projects found below the current directory (--json for JSON output)")
 		fmt.Println("  -gv\tdisplays version information")
+		fmt.Println("  -gw\tgenerates a Gradle/Maven wrapper, optionally for the given version")
+		fmt.Println("  -gx\tforce script execution (scala-cli, kotlin, groovy)")
+		fmt.Println("  -gz\tforce Bazel build")
+		fmt.Println("  -g-allow\ttrusts the project config and wrappers")
+		fmt.Println("  -g-deny\trevokes trust from the project config and wrappers")
 		os.Exit(0)
 	}
 
+	if allow && deny {
+		fmt.Println("You cannot define -g-allow and -g-deny flags at the same time")
+		os.Exit(-1)
+	}
+
+	if allow {
+		gum.AllowProject(gum.NewDefaultContext(false))
+		os.Exit(0)
+	} else if deny {
+		gum.DenyProject(gum.NewDefaultContext(false))
+		os.Exit(0)
+	}
+
+	if list {
+		gum.ListProjects(gum.NewDefaultContext(false), &args)
+		os.Exit(0)
+	}
+
+	if fanOut {
+		os.Exit(gum.FanOut(gum.NewDefaultContext(false), &args))
+	}
+
+	if wrapper {
+		os.Exit(gum.FindWrapper(gum.NewDefaultContext(true), &args).Execute())
+	}
+
 	count := 0
 	if gradleBuild {
 		count = count + 1
@@ -81,9 +130,27 @@ func main() {
 	if antBuild {
 		count = count + 1
 	}
+	if sbtBuild {
+		count = count + 1
+	}
+	if millBuild {
+		count = count + 1
+	}
+	if bazelBuild {
+		count = count + 1
+	}
+	if clojureBuild {
+		count = count + 1
+	}
+	if scriptBuild {
+		count = count + 1
+	}
+	if amperBuild {
+		count = count + 1
+	}
 
 	if count > 1 {
-		fmt.Println("You cannot define -gb, -gg, -gm, gj, or -ga flags at the same time")
+		fmt.Println("You cannot define -gb, -gg, -gi, -gm, -gj, -gl, -gp, -gs, -gx, -gz, or -ga flags at the same time")
 		os.Exit(-1)
 	}
 
@@ -97,6 +164,18 @@ func main() {
 		gum.FindBach(gum.NewDefaultContext(true), &args).Execute()
 	} else if antBuild {
 		gum.FindAnt(gum.NewDefaultContext(true), &args).Execute()
+	} else if sbtBuild {
+		gum.FindSbt(gum.NewDefaultContext(true), &args).Execute()
+	} else if millBuild {
+		gum.FindMill(gum.NewDefaultContext(true), &args).Execute()
+	} else if bazelBuild {
+		gum.FindBazel(gum.NewDefaultContext(true), &args).Execute()
+	} else if clojureBuild {
+		gum.FindClojure(gum.NewDefaultContext(true), &args).Execute()
+	} else if scriptBuild {
+		gum.FindScript(gum.NewDefaultContext(true), &args).Execute()
+	} else if amperBuild {
+		gum.FindAmper(gum.NewDefaultContext(true), &args).Execute()
 	} else {
 		gum.FindTool(&args)
 	}
@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Finds the files changed between base and HEAD, along with uncommitted and untracked files.
+// Only files below dir are returned, as absolute paths.
+func findChangedFiles(dir string, base string) ([]string, error) {
+	lines, err := runGit(dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.Join(lines, "")
+
+	changes := make([]string, 0)
+	for _, args := range [][]string{
+		{"diff", "--name-only", base + "...HEAD"},
+		{"diff", "--name-only", "HEAD"},
+		{"ls-files", "--others", "--exclude-standard", "--full-name"}} {
+		lines, err := runGit(dir, args...)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, lines...)
+	}
+
+	files := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, change := range changes {
+		// paths are relative to the top level directory of the repository
+		if !strings.HasPrefix(change, prefix) || seen[change] {
+			continue
+		}
+		seen[change] = true
+		files = append(files, filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(change, prefix))))
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// Runs git at the given directory, returning the lines written to stdout
+func runGit(dir string, args ...string) ([]string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if stderr.Len() > 0 {
+			return nil, errors.New(strings.TrimSpace(stderr.String()))
+		}
+		return nil, err
+	}
+
+	lines := make([]string, 0)
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if len(strings.TrimSpace(line)) > 0 {
+			lines = append(lines, strings.TrimSpace(line))
+		}
+	}
+	return lines, nil
+}
+
+// Keeps the projects holding at least one of the given files
+func filterAffectedProjects(pwd string, projects []ScannedProject, files []string) []ScannedProject {
+	affected := make([]ScannedProject, 0)
+	for _, p := range projects {
+		if len(findFilesBelow(filepath.Join(pwd, filepath.FromSlash(p.Dir)), files)) > 0 {
+			affected = append(affected, p)
+		}
+	}
+	return affected
+}
+
+func findFilesBelow(dir string, files []string) []string {
+	found := make([]string, 0)
+	for _, file := range files {
+		rel, err := filepath.Rel(dir, file)
+		if err == nil && !strings.HasPrefix(rel, "..") {
+			found = append(found, file)
+		}
+	}
+	return found
+}
+
+// Resolves the paths of the Gradle projects holding the given files, i.e, :libs:core.
+// Returns nil when a file belongs to the root project, as the whole build is affected.
+func resolveAffectedGradleProjects(settingsFile string, files []string) []string {
+	if len(settingsFile) == 0 {
+		return nil
+	}
+
+	projects := readGradleProjects(settingsFile)
+	paths := make([]string, 0)
+	for _, file := range findFilesBelow(filepath.Dir(settingsFile), files) {
+		path := resolveGradleProjectPath(projects, filepath.Dir(file))
+		if path == ":" {
+			return nil
+		}
+		paths = appendUnique(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// Resolves the -pl selectors of the reactor modules holding the given files.
+// Returns nil when a file belongs to the root pom or to a module outside of the reactor,
+// as the whole build is affected.
+func resolveAffectedMavenModules(rootBuildFile string, files []string) []string {
+	if len(rootBuildFile) == 0 {
+		return nil
+	}
+
+	rootdir := filepath.Dir(rootBuildFile)
+	modules := make([]string, 0)
+	for _, file := range findFilesBelow(rootdir, files) {
+		dir := filepath.Dir(file)
+		for dir != rootdir && !isRegularFile(filepath.Join(dir, "pom.xml")) {
+			dir = filepath.Dir(dir)
+		}
+
+		selector := resolveMavenModuleSelector(rootBuildFile, filepath.Join(dir, "pom.xml"))
+		if len(selector) == 0 {
+			return nil
+		}
+		modules = appendUnique(modules, selector)
+	}
+	sort.Strings(modules)
+
+	return modules
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
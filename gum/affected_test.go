@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAffectedProjects(t *testing.T) {
+	// given:
+	pwd := t.TempDir()
+	write := func(file string, content string) {
+		path := filepath.Join(pwd, filepath.FromSlash(file))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=gm", "-c", "user.email=gm@example.com"}, args...)...)
+		cmd.Dir = pwd
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+
+	write("maven/pom.xml", "<project><modules><module>core</module><module>api</module></modules></project>")
+	write("maven/core/pom.xml", "<project></project>")
+	write("maven/core/src/A.java", "class A {}")
+	write("maven/api/pom.xml", "<project></project>")
+	write("gradle/settings.gradle", "include 'app', 'lib'")
+	write("gradle/app/build.gradle", "")
+	write("gradle/lib/build.gradle", "")
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+	write("maven/core/src/A.java", "class A { }")
+	git("commit", "-q", "-a", "-m", "change")
+	write("gradle/lib/src/B.kt", "class B")
+
+	// when:
+	files, err := findChangedFiles(pwd, "HEAD~1")
+
+	// then:
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var checks = []struct {
+		title    string
+		actual   string
+		expected string
+	}{
+		{"ChangedFiles", strings.Join(files, " "), filepath.Join(pwd, "gradle", "lib", "src", "B.kt") + " " + filepath.Join(pwd, "maven", "core", "src", "A.java")},
+		{"MavenModules", strings.Join(resolveAffectedMavenModules(filepath.Join(pwd, "maven", "pom.xml"), files), " "), "core"},
+		{"MavenRoot", strings.Join(resolveAffectedMavenModules(filepath.Join(pwd, "maven", "pom.xml"), []string{filepath.Join(pwd, "maven", "pom.xml")}), " "), ""},
+		{"GradleProjects", strings.Join(resolveAffectedGradleProjects(filepath.Join(pwd, "gradle", "settings.gradle"), files), " "), ":lib"},
+		{"GradleRoot", strings.Join(resolveAffectedGradleProjects(filepath.Join(pwd, "gradle", "settings.gradle"), []string{filepath.Join(pwd, "gradle", "settings.gradle")}), " "), ""},
+		{"GradleTasks", strings.Join(qualifyGradleTasksFor([]string{"--offline", "test", "--tests", "Foo", "-x", "check"}, []string{":app", ":lib"}), " "), "--offline :app:test --tests Foo -x :app:check :lib:test --tests Foo -x :lib:check"},
+	}
+
+	for _, check := range checks {
+		t.Run(check.title, func(t *testing.T) {
+			if check.actual != check.expected {
+				t.Errorf("%s: got %s want %s", check.title, check.actual, check.expected)
+			}
+		})
+	}
+}
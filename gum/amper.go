@@ -0,0 +1,293 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// AmperCommand defines an executable Amper command
+type AmperCommand struct {
+	context          Context
+	config           *Config
+	executable       string
+	args             *ParsedArgs
+	rootDir          string
+	moduleFile       string
+	projectFile      string
+	executableSource string
+}
+
+// Execute executes the given command
+func (c AmperCommand) Execute() int {
+	if !trustProject(c.context, c.config, c.wrappers()...) {
+		c.context.Exit(-1)
+	}
+	c.doConfigureAmper()
+	return c.doExecuteAmper()
+}
+
+// Project local wrappers that must be trusted before execution
+func (c *AmperCommand) wrappers() []string {
+	if c.executableSource == "wrapper" {
+		return []string{c.executable}
+	}
+	return nil
+}
+
+func (c *AmperCommand) doConfigureAmper() {
+	c.context.CheckIsExecutable(c.executable)
+
+	args := make([]string, 0)
+
+	banner := make([]string, 0)
+	banner = append(banner, "Using amper at '"+c.executable+"'")
+	banner = append(banner, "to run project at '"+c.rootDir+"':")
+	debug := c.args.HasGumFlag("gd")
+	skipReplace := c.args.HasGumFlag("gr")
+
+	if debug {
+		c.config.setDebug(debug)
+	}
+	if skipReplace {
+		c.config.amper.setReplace(!skipReplace)
+	}
+	c.debugConfig()
+	otargs := c.args.Tool
+	oargs := c.args.Args
+	rargs := replaceAmperCommands(c.config, c.args)
+
+	args = appendSafe(args, c.args.Tool)
+	c.args.Args = appendSafe(args, rargs)
+
+	c.debugAmper(otargs, oargs, rargs)
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(c.context.GetStdout(), strings.Join(banner, " "))
+	}
+}
+
+func (c *AmperCommand) doExecuteAmper() int {
+	cmd := exec.Command(c.executable, c.args.Args...)
+	cmd.Dir = c.rootDir
+	cmd.Stdout = c.context.GetStdout()
+	cmd.Stderr = c.context.GetStderr()
+	err := cmd.Run()
+	var exerr *exec.ExitError
+	if errors.As(err, &exerr) {
+		return exerr.ExitCode()
+	}
+	if err != nil {
+		fmt.Fprintln(c.context.GetStderr(), err)
+		return -1
+	}
+	return 0
+}
+
+func (c *AmperCommand) debugConfig() {
+	if c.args.HasGumFlag("gc") {
+		c.config.print(c.context.GetStdout())
+		c.context.Exit(0)
+	}
+}
+
+func (c *AmperCommand) debugAmper(otargs []string, oargs []string, rargs []string) {
+	if c.config.general.debug {
+		fmt.Fprintln(c.context.GetStdout(), "replace            = ", c.config.amper.replace)
+		fmt.Fprintln(c.context.GetStdout(), "pwd                = ", c.context.GetWorkingDir())
+		fmt.Fprintln(c.context.GetStdout(), "rootDir            = ", c.rootDir)
+		fmt.Fprintln(c.context.GetStdout(), "projectFile        = ", c.projectFile)
+		fmt.Fprintln(c.context.GetStdout(), "moduleFile         = ", c.moduleFile)
+		fmt.Fprintln(c.context.GetStdout(), "original tool args = ", otargs)
+		fmt.Fprintln(c.context.GetStdout(), "original args      = ", oargs)
+		if c.config.amper.replace {
+			fmt.Fprintln(c.context.GetStdout(), "replaced args      = ", rargs)
+		}
+		fmt.Fprintln(c.context.GetStdout(), "actual args        = ", c.args.Args)
+		fmt.Fprintln(c.context.GetStdout(), "")
+	}
+}
+
+// Replaces lifecycle goals with Amper commands.
+// A mapping value may expand into several arguments, i.e, "publish mavenLocal".
+func replaceAmperCommands(config *Config, args *ParsedArgs) []string {
+	if !config.amper.replace {
+		return args.Args
+	}
+
+	nargs := make([]string, 0)
+	for _, arg := range replaceArgs(args.Args, config.amper.mappings, false) {
+		nargs = append(nargs, strings.Fields(arg)...)
+	}
+	return nargs
+}
+
+// FindAmper finds and executes the amper wrapper
+func FindAmper(context Context, args *ParsedArgs) *AmperCommand {
+	pwd := context.GetWorkingDir()
+
+	moduleFile, noModuleFile := findAmperModuleFile(context, pwd)
+	projectFile, noProjectFile := findAmperProjectFile(context, pwd)
+	rootdir := resolveAmperRootDir(context, moduleFile, projectFile)
+	config := ReadConfig(context, rootdir)
+	quiet := args.HasGumFlag("gq")
+
+	if quiet {
+		config.setQuiet(quiet)
+	}
+
+	amperw, noWrapper := findAmperWrapperExec(context, pwd)
+	amper, noAmper := findAmperExec(context)
+
+	var executable string
+	var executableSource string
+	if noWrapper == nil {
+		executable = amperw
+		executableSource = "wrapper"
+	} else if noAmper == nil {
+		warnNoAmperWrapper(context, config)
+		executable = amper
+	} else {
+		warnNoAmper(context, config)
+
+		if context.IsExplicit() {
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	if noModuleFile != nil && noProjectFile != nil {
+		if context.IsExplicit() {
+			fmt.Fprintln(context.GetStdout(), "No Amper project found")
+			fmt.Fprintln(context.GetStdout())
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	return &AmperCommand{
+		context:          context,
+		config:           config,
+		executable:       executable,
+		args:             args,
+		rootDir:          rootdir,
+		moduleFile:       moduleFile,
+		projectFile:      projectFile,
+		executableSource: executableSource}
+}
+
+func resolveAmperRootDir(context Context,
+	moduleFile string,
+	projectFile string) string {
+
+	if context.FileExists(projectFile) {
+		return filepath.Dir(projectFile)
+	}
+	return filepath.Dir(moduleFile)
+}
+
+func warnNoAmperWrapper(context Context, config *Config) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(context.GetStdout(), "No %s set up for this project. ", resolveAmperWrapperExec(context))
+		fmt.Fprintln(context.GetStdout())
+		fmt.Fprintln(context.GetStdout(), "Please consider setting one up.")
+		fmt.Fprintln(context.GetStdout(), "(https://github.com/JetBrains/amper/blob/main/docs/Usage.md)")
+		fmt.Fprintln(context.GetStdout())
+	}
+}
+
+func warnNoAmper(context Context, config *Config) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(context.GetStdout(), "No %s found. Please set up the Amper wrapper.", resolveAmperWrapperExec(context))
+		fmt.Fprintln(context.GetStdout())
+		fmt.Fprintln(context.GetStdout(), "(https://github.com/JetBrains/amper/blob/main/docs/Usage.md)")
+		fmt.Fprintln(context.GetStdout())
+	}
+}
+
+// Finds the amper executable
+func findAmperExec(context Context) (string, error) {
+	amper := resolveAmperWrapperExec(context)
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], amper)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New(amper + " not found")
+}
+
+// Finds the amper wrapper (if it exists)
+func findAmperWrapperExec(context Context, dir string) (string, error) {
+	wrapper := resolveAmperWrapperExec(context)
+	path := filepath.Join(dir, wrapper)
+	if isRegularFile(path) {
+		return filepath.Abs(path)
+	}
+
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New(wrapper + " not found")
+	}
+
+	return findAmperWrapperExec(context, parentdir)
+}
+
+// Finds the nearest module.yaml
+func findAmperModuleFile(context Context, dir string) (string, error) {
+	path := filepath.Join(dir, "module.yaml")
+	if context.FileExists(path) {
+		return filepath.Abs(path)
+	}
+
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New("Did not find module.yaml")
+	}
+
+	return findAmperModuleFile(context, parentdir)
+}
+
+// Finds the nearest project.yaml
+func findAmperProjectFile(context Context, dir string) (string, error) {
+	path := filepath.Join(dir, "project.yaml")
+	if context.FileExists(path) {
+		return filepath.Abs(path)
+	}
+
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New("Did not find project.yaml")
+	}
+
+	return findAmperProjectFile(context, parentdir)
+}
+
+// Resolves the amper wrapper executable (OS dependent)
+func resolveAmperWrapperExec(context Context) string {
+	if context.IsWindows() {
+		return "amper.bat"
+	}
+	return "amper"
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAmperProjectWithWrapper(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "amper", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "amper", "project"))
+	pwd := filepath.Join(root, "app")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "compile", "install"})
+	cmd := FindAmper(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(root, "amper")},
+		{"RootDir", cmd.rootDir, root},
+		{"ProjectFile", cmd.projectFile, filepath.Join(root, "project.yaml")},
+		{"ModuleFile", cmd.moduleFile, filepath.Join(pwd, "module.yaml")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+
+	cmd.doConfigureAmper()
+	actual := strings.Join(cmd.args.Args, " ")
+	if actual != "build publish mavenLocal" {
+		t.Errorf("args: got %s, want build publish mavenLocal", actual)
+	}
+}
+
+func TestAmperSingleModuleWithoutWrapper(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "amper", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "amper", "single"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "-gr", "build"})
+	cmd := FindAmper(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "amper")},
+		{"RootDir", cmd.rootDir, pwd},
+		{"ProjectFile", cmd.projectFile, ""},
+		{"ModuleFile", cmd.moduleFile, filepath.Join(pwd, "module.yaml")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
@@ -19,7 +19,6 @@ package gum
 import (
 	"errors"
 	"fmt"
-	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
@@ -34,10 +33,22 @@ type AntCommand struct {
 	args              *ParsedArgs
 	buildFile         string
 	explicitBuildFile string
+	executableSource  string
+	java              *javaRuntime
 }
 
 // Execute executes the given command
 func (c AntCommand) Execute() int {
+	withheld := c.config.withheld != nil
+	if !trustProject(c.context, c.config) {
+		c.context.Exit(-1)
+	}
+	if withheld {
+		if executable, source, err := findAntExec(c.context, c.config); err == nil {
+			c.executable = executable
+			c.executableSource = source
+		}
+	}
 	c.doConfigureAnt()
 	return c.doExecuteAnt()
 }
@@ -56,6 +67,7 @@ func (c *AntCommand) doConfigureAnt() {
 		c.config.setDebug(debug)
 	}
 	c.debugConfig()
+	c.java = resolveJavaRuntime(c.context, c.config)
 	oargs := c.args.Args
 
 	if len(c.explicitBuildFile) > 0 {
@@ -75,38 +87,46 @@ func (c *AntCommand) doConfigureAnt() {
 	c.debugAnt(c.config, oargs)
 
 	if !c.config.general.quiet {
-		fmt.Println(strings.Join(banner, " "))
+		fmt.Fprintln(c.context.GetStdout(), strings.Join(banner, " "))
 	}
 }
 
 func (c *AntCommand) doExecuteAnt() int {
 	cmd := exec.Command(c.executable, c.args.Args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Env = c.java.environment(c.context)
+	cmd.Stdout = c.context.GetStdout()
+	cmd.Stderr = c.context.GetStderr()
+	cmd.Dir = c.context.GetWorkingDir()
 	err := cmd.Run()
 	var exerr *exec.ExitError
 	if errors.As(err, &exerr) {
 		return exerr.ExitCode()
 	}
+	if err != nil {
+		fmt.Fprintln(c.context.GetStderr(), err)
+		return -1
+	}
 	return 0
 }
 
 func (c *AntCommand) debugConfig() {
 	if c.args.HasGumFlag("gc") {
-		c.config.print()
-		os.Exit(0)
+		c.config.print(c.context.GetStdout())
+		c.context.Exit(0)
 	}
 }
 
 func (c *AntCommand) debugAnt(config *Config, oargs []string) {
 	if c.config.general.debug {
-		fmt.Println("rootdir            = ", c.rootdir)
-		fmt.Println("executable         = ", c.executable)
-		fmt.Println("buildFile          = ", c.buildFile)
-		fmt.Println("explicitBuildFile  = ", c.explicitBuildFile)
-		fmt.Println("original args      = ", oargs)
-		fmt.Println("actual args        = ", c.args.Args)
-		fmt.Println("")
+		fmt.Fprintln(c.context.GetStdout(), "rootdir            = ", c.rootdir)
+		fmt.Fprintln(c.context.GetStdout(), "executable         = ", c.executable)
+		fmt.Fprintln(c.context.GetStdout(), "executable source  = ", c.executableSource)
+		fmt.Fprintln(c.context.GetStdout(), "buildFile          = ", c.buildFile)
+		fmt.Fprintln(c.context.GetStdout(), "explicitBuildFile  = ", c.explicitBuildFile)
+		fmt.Fprintln(c.context.GetStdout(), "java               = ", c.java)
+		fmt.Fprintln(c.context.GetStdout(), "original args      = ", oargs)
+		fmt.Fprintln(c.context.GetStdout(), "actual args        = ", c.args.Args)
+		fmt.Fprintln(c.context.GetStdout(), "")
 	}
 }
 
@@ -114,7 +134,6 @@ func (c *AntCommand) debugAnt(config *Config, oargs []string) {
 func FindAnt(context Context, args *ParsedArgs) *AntCommand {
 	pwd := context.GetWorkingDir()
 
-	ant, noAnt := findAntExec(context)
 	explicitBuildFileSet, explicitBuildFile := findExplicitAntBuildFile(args)
 	buildFile, noBuildFile := findAntBuildFile(context, pwd)
 
@@ -126,6 +145,8 @@ func FindAnt(context Context, args *ParsedArgs) *AntCommand {
 		config.setQuiet(quiet)
 	}
 
+	ant, antSource, noAnt := findAntExec(context, config)
+
 	var executable string
 	if noAnt == nil {
 		executable = ant
@@ -143,26 +164,28 @@ func FindAnt(context Context, args *ParsedArgs) *AntCommand {
 			context:           context,
 			config:            config,
 			executable:        executable,
+			executableSource:  antSource,
 			args:              args,
 			explicitBuildFile: explicitBuildFile}
 	}
 
 	if noBuildFile != nil {
 		if context.IsExplicit() {
-			fmt.Println("No Ant project found")
-			fmt.Println()
+			fmt.Fprintln(context.GetStdout(), "No Ant project found")
+			fmt.Fprintln(context.GetStdout())
 			context.Exit(-1)
 		}
 		return nil
 	}
 
 	return &AntCommand{
-		context:    context,
-		config:     config,
-		rootdir:    rootdir,
-		executable: executable,
-		args:       args,
-		buildFile:  buildFile}
+		context:          context,
+		config:           config,
+		rootdir:          rootdir,
+		executable:       executable,
+		executableSource: antSource,
+		args:             args,
+		buildFile:        buildFile}
 }
 
 func resolveAntRootDir(context Context,
@@ -177,26 +200,16 @@ func resolveAntRootDir(context Context,
 
 func warnNoAnt(context Context, config *Config) {
 	if !config.general.quiet && context.IsExplicit() {
-		fmt.Printf("No %s found in path. Please install Ant.", resolveAntExec(context))
-		fmt.Println()
-		fmt.Println("(https://ant.apache.org/bindownload.cgi)")
-		fmt.Println()
+		fmt.Fprintf(context.GetStdout(), "No %s found in path. Please install Ant.", resolveAntExec(context))
+		fmt.Fprintln(context.GetStdout())
+		fmt.Fprintln(context.GetStdout(), "(https://ant.apache.org/bindownload.cgi)")
+		fmt.Fprintln(context.GetStdout())
 	}
 }
 
 // Finds the ant executable
-func findAntExec(context Context) (string, error) {
-	ant := resolveAntExec(context)
-	paths := context.GetPaths()
-
-	for i := range paths {
-		name := filepath.Join(paths[i], ant)
-		if context.FileExists(name) {
-			return filepath.Abs(name)
-		}
-	}
-
-	return "", errors.New(ant + " not found")
+func findAntExec(context Context, config *Config) (string, string, error) {
+	return locateExecutable(context, "ant", "ANT_HOME", config.ant.executable, resolveAntExec(context))
 }
 
 func findExplicitAntBuildFile(args *ParsedArgs) (bool, string) {
@@ -221,17 +234,16 @@ func findExplicitAntBuildFile(args *ParsedArgs) (bool, string) {
 
 // Finds the nearest build.xml
 func findAntBuildFile(context Context, dir string) (string, error) {
-	parentdir := filepath.Join(dir, "..")
-
-	if parentdir == dir {
-		return "", errors.New("Did not find build.xml")
-	}
-
 	path := filepath.Join(dir, "build.xml")
 	if context.FileExists(path) {
 		return filepath.Abs(path)
 	}
 
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New("Did not find build.xml")
+	}
+
 	return findAntBuildFile(context, parentdir)
 }
 
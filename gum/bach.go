@@ -19,7 +19,7 @@ package gum
 import (
 	"errors"
 	"fmt"
-	"os"
+	"io/ioutil"
 	"os/exec"
 	"path/filepath"
 	"strings"
@@ -31,11 +31,18 @@ type BachCommand struct {
 	config     *Config
 	rootdir    string
 	executable string
+	generation string
+	version    string
+	source     string
 	args       *ParsedArgs
+	java       *javaRuntime
 }
 
 // Execute executes the given command
 func (c BachCommand) Execute() int {
+	if !trustProject(c.context, c.config) {
+		c.context.Exit(-1)
+	}
 	c.doConfigureBach()
 	return c.doExecuteBach()
 }
@@ -55,9 +62,10 @@ func (c *BachCommand) doConfigureBach() {
 		c.config.setDebug(debug)
 	}
 	c.debugConfig()
+	c.java = resolveJavaRuntime(c.context, c.config)
 	oargs := c.args.Args
 
-	c.executable = execParts[0]
+	c.executable = c.java.executable(c.context, execParts[0])
 
 	args = appendSafe(args, execParts[1:])
 	args = appendSafe(args, c.args.Tool)
@@ -66,36 +74,44 @@ func (c *BachCommand) doConfigureBach() {
 	c.debugBach(c.config, oargs)
 
 	if !c.config.general.quiet {
-		fmt.Println(strings.Join(banner, " "))
+		fmt.Fprintln(c.context.GetStdout(), strings.Join(banner, " "))
 	}
 }
 
 func (c *BachCommand) doExecuteBach() int {
 	cmd := exec.Command(c.executable, c.args.Args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Env = c.java.environment(c.context)
+	cmd.Stdout = c.context.GetStdout()
+	cmd.Stderr = c.context.GetStderr()
 	err := cmd.Run()
 	var exerr *exec.ExitError
 	if errors.As(err, &exerr) {
 		return exerr.ExitCode()
 	}
+	if err != nil {
+		fmt.Fprintln(c.context.GetStderr(), err)
+		return -1
+	}
 	return 0
 }
 
 func (c *BachCommand) debugConfig() {
 	if c.args.HasGumFlag("gc") {
-		c.config.print()
-		os.Exit(0)
+		c.config.print(c.context.GetStdout())
+		c.context.Exit(0)
 	}
 }
 
 func (c *BachCommand) debugBach(config *Config, oargs []string) {
 	if c.config.general.debug {
-		fmt.Println("rootdir            = ", c.rootdir)
-		fmt.Println("executable         = ", c.executable)
-		fmt.Println("original args      = ", oargs)
-		fmt.Println("actual args        = ", c.args.Args)
-		fmt.Println("")
+		fmt.Fprintln(c.context.GetStdout(), "rootdir            = ", c.rootdir)
+		fmt.Fprintln(c.context.GetStdout(), "executable         = ", c.executable)
+		fmt.Fprintln(c.context.GetStdout(), "generation         = ", c.generation)
+		fmt.Fprintln(c.context.GetStdout(), "version            = ", c.version+" ("+c.source+")")
+		fmt.Fprintln(c.context.GetStdout(), "java               = ", c.java)
+		fmt.Fprintln(c.context.GetStdout(), "original args      = ", oargs)
+		fmt.Fprintln(c.context.GetStdout(), "actual args        = ", c.args.Args)
+		fmt.Fprintln(c.context.GetStdout(), "")
 	}
 }
 
@@ -111,7 +127,8 @@ func FindBach(context Context, args *ParsedArgs) *BachCommand {
 		config.setQuiet(quiet)
 	}
 
-	executable, noExecutable := findBachExecutable(context, config, rootdir)
+	version, source := findBachVersion(context, config, rootdir)
+	executable, generation, noExecutable := findBachExecutable(context, version, rootdir)
 
 	if noExecutable != nil {
 		warnNoBach(context, config)
@@ -124,8 +141,8 @@ func FindBach(context Context, args *ParsedArgs) *BachCommand {
 
 	if noRootdir != nil {
 		if context.IsExplicit() {
-			fmt.Println("No Bach project found")
-			fmt.Println()
+			fmt.Fprintln(context.GetStdout(), "No Bach project found")
+			fmt.Fprintln(context.GetStdout())
 			context.Exit(-1)
 		}
 		return nil
@@ -135,8 +152,8 @@ func FindBach(context Context, args *ParsedArgs) *BachCommand {
 	r, _ := filepath.Abs(rootdir)
 	if p != r {
 		if context.IsExplicit() {
-			fmt.Println("Bach must be invoked from " + rootdir)
-			fmt.Println()
+			fmt.Fprintln(context.GetStdout(), "Bach must be invoked from "+rootdir)
+			fmt.Fprintln(context.GetStdout())
 			context.Exit(-1)
 		}
 		return nil
@@ -147,51 +164,73 @@ func FindBach(context Context, args *ParsedArgs) *BachCommand {
 		config:     config,
 		rootdir:    rootdir,
 		executable: executable,
+		generation: generation,
+		version:    version,
+		source:     source,
 		args:       args}
 }
 
 func resolveBachRootDir(context Context, dir string) (string, error) {
-	parentdir := filepath.Join(dir, "..")
-
-	if parentdir == dir {
-		return "", errors.New("Did not find root")
-	}
-
 	path := filepath.Join(dir, ".bach")
 	if context.FileExists(path) {
 		return filepath.Abs(dir)
 	}
 
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New("Did not find root")
+	}
+
 	return resolveBachRootDir(context, parentdir)
 }
 
 func warnNoBach(context Context, config *Config) {
 	if !config.general.quiet && context.IsExplicit() {
-		fmt.Println("No java/jshell found in path. Please install Java 16+")
+		fmt.Fprintln(context.GetStdout(), "No java/jshell found in path. Please install Java 16+")
 	}
 }
 
-func findBachExecutable(context Context, config *Config, dir string) (string, error) {
+// Finds the Bach version pinned by the project at .bach/bach.version, falling back to [bach] version
+func findBachVersion(context Context, config *Config, dir string) (string, string) {
+	path := filepath.Join(dir, ".bach", "bach.version")
+	if context.FileExists(path) {
+		data, err := ioutil.ReadFile(path)
+		version := strings.TrimSpace(string(data))
+		if err == nil && len(version) > 0 {
+			return version, path
+		}
+	}
+
+	return config.bach.version, "[bach] version"
+}
+
+// Finds the command that launches Bach, based on the project layout (generation):
+// - args: Bach 17+, launched with java @bach
+// - module: Bach 16, launched from modules found at .bach/bin or .bach/cache
+// - jshell: bootstraps Bach with jshell from the given version's build.jsh
+func findBachExecutable(context Context, version string, dir string) (string, string, error) {
 	java, noJava := findExecutable(context, dir, "java")
 	jshell, noJshell := findExecutable(context, dir, "jshell")
 
 	if noJava != nil {
 		if noJshell != nil {
-			return "", errors.New("No java nor jshell found")
+			return "", "", errors.New("No java nor jshell found")
 		}
-		return jshell + " https://github.com/sormuras/bach/releases/download/" + config.bach.version + "/build.jsh", nil
+		return jshell + " https://github.com/sormuras/bach/releases/download/" + version + "/build.jsh", "jshell", nil
 	}
 
 	bin := filepath.Join(dir, ".bach", "bin")
 	cache := filepath.Join(dir, ".bach", "cache")
-	if context.FileExists(bin) {
-		return java + " -p " + bin + " -m com.github.sormuras.bach build", nil
+	if isRegularFile(filepath.Join(dir, "bach")) {
+		return java + " @bach", "args", nil
+	} else if context.FileExists(bin) {
+		return java + " -p " + bin + " -m com.github.sormuras.bach build", "module", nil
 	} else if context.FileExists(cache) {
-		return java + " -p " + cache + " -m com.github.sormuras.bach build", nil
+		return java + " -p " + cache + " -m com.github.sormuras.bach build", "module", nil
 	} else if noJshell == nil {
-		return jshell + " https://github.com/sormuras/bach/releases/download/" + config.bach.version + "/build.jsh", nil
+		return jshell + " https://github.com/sormuras/bach/releases/download/" + version + "/build.jsh", "jshell", nil
 	} else {
-		return "", errors.New("jshell not found")
+		return "", "", errors.New("jshell not found")
 	}
 }
 
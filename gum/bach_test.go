@@ -112,3 +112,99 @@ func TestBachWithoutExecutables(t *testing.T) {
 		t.Error("Expected a nil command but got something")
 	}
 }
+
+func TestBachProjectWithArgumentFile(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "bach", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "bach", "project-with-args"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "build"})
+	cmd := FindBach(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	cmd.doConfigureBach()
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "java")},
+		{"Generation", cmd.generation, "args"},
+		{"Version", cmd.version, "17.0.0"},
+		{"VersionSource", cmd.source, filepath.Join(pwd, ".bach", "bach.version")},
+		{"Args", strings.Join(cmd.args.Args, " "), "@bach build"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestBachProjectWithPinnedVersion(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "bach", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "bach", "project-with-version"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindBach(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "jshell") + " https://github.com/sormuras/bach/releases/download/16.0.1/build.jsh"},
+		{"Generation", cmd.generation, "jshell"},
+		{"Version", cmd.version, "16.0.1"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestBachVersionFallsBackToConfig(t *testing.T) {
+	// given:
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "bach", "project-with-cache"))
+	context := testContext{workingDir: pwd}
+
+	config := newConfig()
+	config.bach.version = "16.0.1"
+	config.merge(nil)
+
+	// when:
+	version, source := findBachVersion(context, config, pwd)
+
+	// then:
+	if version != "16.0.1" || source != "[bach] version" {
+		t.Errorf("version: got %s (%s), want 16.0.1 ([bach] version)", version, source)
+	}
+}
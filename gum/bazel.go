@@ -0,0 +1,338 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// BazelCommand defines an executable Bazel command
+type BazelCommand struct {
+	context          Context
+	config           *Config
+	rootdir          string
+	executable       string
+	args             *ParsedArgs
+	workspaceFile    string
+	executableSource string
+}
+
+// Execute executes the given command
+func (c BazelCommand) Execute() int {
+	if !trustProject(c.context, c.config, c.wrappers()...) {
+		c.context.Exit(-1)
+	}
+	c.doConfigureBazel()
+	return c.doExecuteBazel()
+}
+
+// Project local wrappers that must be trusted before execution
+func (c *BazelCommand) wrappers() []string {
+	if c.executableSource == "wrapper" {
+		return []string{c.executable}
+	}
+	return nil
+}
+
+func (c *BazelCommand) doConfigureBazel() {
+	c.context.CheckIsExecutable(c.executable)
+
+	args := make([]string, 0)
+
+	banner := make([]string, 0)
+	banner = append(banner, "Using bazel at '"+c.executable+"'")
+	banner = append(banner, "to run workspace at '"+c.rootdir+"':")
+	debug := c.args.HasGumFlag("gd")
+	skipReplace := c.args.HasGumFlag("gr")
+
+	if debug {
+		c.config.setDebug(debug)
+	}
+	if skipReplace {
+		c.config.bazel.setReplace(!skipReplace)
+	}
+	c.debugConfig()
+	otargs := c.args.Tool
+	oargs := c.args.Args
+	rargs := replaceBazelCommand(c.config, c.args)
+	target := resolveBazelTargetPattern(c.rootdir, c.context.GetWorkingDir())
+	rargs = appendBazelTargetPattern(rargs, target)
+
+	// flags given before the command are command options, Bazel reads those preceding it as startup options
+	if len(rargs) > 0 {
+		args = append(args, rargs[0])
+		args = appendSafe(args, c.args.Tool)
+		c.args.Args = appendSafe(args, rargs[1:])
+	} else {
+		c.args.Args = appendSafe(args, c.args.Tool)
+	}
+
+	c.debugBazel(otargs, oargs, rargs, target)
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(c.context.GetStdout(), strings.Join(banner, " "))
+	}
+}
+
+func (c *BazelCommand) doExecuteBazel() int {
+	cmd := exec.Command(c.executable, c.args.Args...)
+	cmd.Dir = c.context.GetWorkingDir()
+	cmd.Stdout = c.context.GetStdout()
+	cmd.Stderr = c.context.GetStderr()
+	err := cmd.Run()
+	var exerr *exec.ExitError
+	if errors.As(err, &exerr) {
+		return exerr.ExitCode()
+	}
+	if err != nil {
+		fmt.Fprintln(c.context.GetStderr(), err)
+		return -1
+	}
+	return 0
+}
+
+func (c *BazelCommand) debugConfig() {
+	if c.args.HasGumFlag("gc") {
+		c.config.print(c.context.GetStdout())
+		c.context.Exit(0)
+	}
+}
+
+func (c *BazelCommand) debugBazel(otargs []string, oargs []string, rargs []string, target string) {
+	if c.config.general.debug {
+		fmt.Fprintln(c.context.GetStdout(), "replace            = ", c.config.bazel.replace)
+		fmt.Fprintln(c.context.GetStdout(), "pwd                = ", c.context.GetWorkingDir())
+		fmt.Fprintln(c.context.GetStdout(), "rootdir            = ", c.rootdir)
+		fmt.Fprintln(c.context.GetStdout(), "workspaceFile      = ", c.workspaceFile)
+		fmt.Fprintln(c.context.GetStdout(), "executable         = ", c.executable)
+		fmt.Fprintln(c.context.GetStdout(), "target pattern     = ", target)
+		fmt.Fprintln(c.context.GetStdout(), "original tool args = ", otargs)
+		fmt.Fprintln(c.context.GetStdout(), "original args      = ", oargs)
+		if c.config.bazel.replace {
+			fmt.Fprintln(c.context.GetStdout(), "replaced args      = ", rargs)
+		}
+		fmt.Fprintln(c.context.GetStdout(), "actual args        = ", c.args.Args)
+		fmt.Fprintln(c.context.GetStdout(), "")
+	}
+}
+
+// Replaces the Bazel command, which is the first non flag argument
+func replaceBazelCommand(config *Config, args *ParsedArgs) []string {
+	if !config.bazel.replace || len(args.Args) == 0 {
+		return args.Args
+	}
+
+	rargs := make([]string, len(args.Args))
+	copy(rargs, args.Args)
+	rargs[0] = replaceArgs(rargs[:1], config.bazel.mappings, false)[0]
+	return rargs
+}
+
+// Appends the given target pattern if the command accepts targets and none were given
+func appendBazelTargetPattern(args []string, target string) []string {
+	if len(args) == 0 {
+		return args
+	}
+
+	switch args[0] {
+	case "build", "test", "coverage":
+		for _, arg := range args[1:] {
+			if arg == "--" || isBazelTargetPattern(arg) {
+				return args
+			}
+		}
+		return append(args, target)
+	}
+
+	return args
+}
+
+// Checks if the given argument is a target pattern, either absolute (//pkg:target, @repo//pkg)
+// or relative to the working directory (pkg:target, :target, pkg/sub, pkg/...)
+func isBazelTargetPattern(arg string) bool {
+	if strings.HasPrefix(arg, "-") {
+		return false
+	}
+	if strings.HasPrefix(arg, "//") || strings.HasPrefix(arg, "@") {
+		return true
+	}
+	// absolute paths and key=value pairs are option values
+	if strings.HasPrefix(arg, "/") || strings.Contains(arg, "=") {
+		return false
+	}
+	return strings.Contains(arg, ":") || strings.Contains(arg, "/") || strings.HasSuffix(arg, "...")
+}
+
+// Resolves the target pattern matching all packages below dir
+func resolveBazelTargetPattern(rootdir string, dir string) string {
+	rel, err := filepath.Rel(rootdir, dir)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "//..."
+	}
+	return "//" + filepath.ToSlash(rel) + "/..."
+}
+
+// FindBazel finds and executes bazelisk/bazel
+func FindBazel(context Context, args *ParsedArgs) *BazelCommand {
+	pwd := context.GetWorkingDir()
+
+	workspaceFile, noWorkspaceFile := findBazelWorkspaceFile(context, pwd)
+	rootdir := filepath.Dir(workspaceFile)
+	config := ReadConfig(context, rootdir)
+	quiet := args.HasGumFlag("gq")
+
+	if quiet {
+		config.setQuiet(quiet)
+	}
+
+	bazelw, noWrapper := findBazelWrapperExec(context, rootdir)
+	bazelisk, noBazelisk := findBazeliskExec(context)
+	bazel, noBazel := findBazelExec(context)
+
+	var executable string
+	var executableSource string
+	if noWrapper == nil {
+		executable = bazelw
+		executableSource = "wrapper"
+	} else if noBazelisk == nil {
+		executable = bazelisk
+	} else if noBazel == nil {
+		executable = bazel
+	} else {
+		warnNoBazel(context, config)
+
+		if context.IsExplicit() {
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	if noWorkspaceFile != nil {
+		if context.IsExplicit() {
+			fmt.Fprintln(context.GetStdout(), "No Bazel workspace found")
+			fmt.Fprintln(context.GetStdout())
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	return &BazelCommand{
+		context:          context,
+		config:           config,
+		rootdir:          rootdir,
+		executable:       executable,
+		args:             args,
+		workspaceFile:    workspaceFile,
+		executableSource: executableSource}
+}
+
+func warnNoBazel(context Context, config *Config) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(context.GetStdout(), "No %s nor %s found in path. Please install Bazelisk.", resolveBazeliskExec(context), resolveBazelExec(context))
+		fmt.Fprintln(context.GetStdout())
+		fmt.Fprintln(context.GetStdout(), "(https://github.com/bazelbuild/bazelisk)")
+		fmt.Fprintln(context.GetStdout())
+	}
+}
+
+// Finds the bazel executable
+func findBazelExec(context Context) (string, error) {
+	bazel := resolveBazelExec(context)
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], bazel)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New(bazel + " not found")
+}
+
+// Finds the bazelisk executable
+func findBazeliskExec(context Context) (string, error) {
+	bazelisk := resolveBazeliskExec(context)
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], bazelisk)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New(bazelisk + " not found")
+}
+
+// Finds the tools/bazel wrapper (if it exists)
+func findBazelWrapperExec(context Context, dir string) (string, error) {
+	path := filepath.Join(dir, "tools", resolveBazelExec(context))
+	if isRegularFile(path) {
+		return filepath.Abs(path)
+	}
+
+	return "", errors.New("tools/bazel not found")
+}
+
+// Finds the nearest workspace boundary file
+// Checks the following paths in order:
+// - MODULE.bazel
+// - REPO.bazel
+// - WORKSPACE.bazel
+// - WORKSPACE
+func findBazelWorkspaceFile(context Context, dir string) (string, error) {
+	var workspaceFiles [4]string
+	workspaceFiles[0] = "MODULE.bazel"
+	workspaceFiles[1] = "REPO.bazel"
+	workspaceFiles[2] = "WORKSPACE.bazel"
+	workspaceFiles[3] = "WORKSPACE"
+
+	for i := range workspaceFiles {
+		path := filepath.Join(dir, workspaceFiles[i])
+		if context.FileExists(path) {
+			return filepath.Abs(path)
+		}
+	}
+
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New("Did not find Bazel workspace file")
+	}
+
+	return findBazelWorkspaceFile(context, parentdir)
+}
+
+// Resolves the bazel executable (OS dependent)
+func resolveBazelExec(context Context) string {
+	if context.IsWindows() {
+		return "bazel.exe"
+	}
+	return "bazel"
+}
+
+// Resolves the bazelisk executable (OS dependent)
+func resolveBazeliskExec(context Context) string {
+	if context.IsWindows() {
+		return "bazelisk.exe"
+	}
+	return "bazelisk"
+}
@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBazelModuleWithBazelisk(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "bazel", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "bazel", "module"))
+	pwd := filepath.Join(root, "path", "to", "pkg")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "verify", "--test_output=errors"})
+	cmd := FindBazel(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "bazelisk")},
+		{"RootDir", cmd.rootdir, root},
+		{"WorkspaceFile", cmd.workspaceFile, filepath.Join(root, "MODULE.bazel")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+
+	cmd.doConfigureBazel()
+	expected := []string{"test", "--test_output=errors", "//path/to/pkg/..."}
+	if len(cmd.args.Args) != len(expected) {
+		t.Errorf("args: got %s, want %s", cmd.args.Args, expected)
+		return
+	}
+	for i := range expected {
+		if cmd.args.Args[i] != expected[i] {
+			t.Errorf("args: got %s, want %s", cmd.args.Args, expected)
+		}
+	}
+}
+
+func TestBazelModuleWithExplicitTarget(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "bazel", "bin-without-bazelisk"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "bazel", "module"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: root,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "build", "//path/to/pkg:lib"})
+	cmd := FindBazel(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	if cmd.executable != filepath.Join(bin, "bazel") {
+		t.Errorf("Executable: got %s, want %s", cmd.executable, filepath.Join(bin, "bazel"))
+	}
+
+	cmd.doConfigureBazel()
+	if len(cmd.args.Args) != 2 || cmd.args.Args[1] != "//path/to/pkg:lib" {
+		t.Errorf("args: got %s, want [build //path/to/pkg:lib]", cmd.args.Args)
+	}
+}
+
+func TestBazelWorkspaceWithWrapper(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "bazel", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "bazel", "workspace-with-wrapper"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: root,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "compile"})
+	cmd := FindBazel(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(root, "tools", "bazel")},
+		{"RootDir", cmd.rootdir, root},
+		{"WorkspaceFile", cmd.workspaceFile, filepath.Join(root, "WORKSPACE")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+
+	cmd.doConfigureBazel()
+	if len(cmd.args.Args) != 2 || cmd.args.Args[0] != "build" || cmd.args.Args[1] != "//..." {
+		t.Errorf("args: got %s, want [build //...]", cmd.args.Args)
+	}
+}
+
+func TestBazelWithoutExecutables(t *testing.T) {
+	// given:
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "bazel", "module"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindBazel(context, &args)
+
+	// then:
+	if cmd != nil {
+		t.Error("Expected a nil command but got something")
+	}
+}
+
+func TestBazelTargetPattern(t *testing.T) {
+	var checks = []struct {
+		title, args, expected string
+	}{
+		{"NoTarget", "build", "build //pkg/..."},
+		{"Absolute", "build //foo:bar", "build //foo:bar"},
+		{"External", "build @repo//foo", "build @repo//foo"},
+		{"Relative", "build foo:bar", "build foo:bar"},
+		{"RelativeLocal", "test :bar", "test :bar"},
+		{"RelativeRecursive", "test pkg/...", "test pkg/..."},
+		{"RelativePackage", "build pkg/sub", "build pkg/sub"},
+		{"OptionValue", "build --output_groups=foo:bar", "build --output_groups=foo:bar //pkg/..."},
+		{"NotAcceptingTargets", "info", "info"},
+	}
+
+	for _, check := range checks {
+		t.Run(check.title, func(t *testing.T) {
+			actual := strings.Join(appendBazelTargetPattern(strings.Fields(check.args), "//pkg/..."), " ")
+			if actual != check.expected {
+				t.Errorf("got %s, want %s", actual, check.expected)
+			}
+		})
+	}
+}
+
+func TestBazelFlagsFollowCommand(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "bazel", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "bazel", "module"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: root,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "--config=ci", "build", "//path/to/pkg:lib"})
+	cmd := FindBazel(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	cmd.doConfigureBazel()
+	actual := strings.Join(cmd.args.Args, " ")
+	if actual != "build --config=ci //path/to/pkg:lib" {
+		t.Errorf("args: got %s, want build --config=ci //path/to/pkg:lib", actual)
+	}
+}
@@ -0,0 +1,301 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// WrapperCommand defines an executable command that generates a Gradle or Maven wrapper
+type WrapperCommand struct {
+	context    Context
+	config     *Config
+	args       *ParsedArgs
+	tool       string
+	executable string
+	rootDir    string
+	version    *toolVersion
+	java       *javaRuntime
+}
+
+// Execute executes the given command
+func (c WrapperCommand) Execute() int {
+	withheld := c.config.withheld != nil
+	if !trustProject(c.context, c.config) {
+		c.context.Exit(-1)
+	}
+	if withheld {
+		if executable, err := findWrapperToolExec(c.context, c.config, c.tool); err == nil {
+			c.executable = executable
+		}
+	}
+	c.java = resolveJavaRuntime(c.context, c.config)
+
+	args := c.resolveToolArgs()
+	if c.config.general.debug {
+		fmt.Fprintln(c.context.GetStdout(), "tool               = ", c.tool)
+		fmt.Fprintln(c.context.GetStdout(), "executable         = ", c.executable)
+		fmt.Fprintln(c.context.GetStdout(), "rootDir            = ", c.rootDir)
+		fmt.Fprintln(c.context.GetStdout(), "version            = ", c.version)
+		fmt.Fprintln(c.context.GetStdout(), "java               = ", c.java)
+		fmt.Fprintln(c.context.GetStdout(), "actual args        = ", args)
+		fmt.Fprintln(c.context.GetStdout(), "")
+	}
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(c.context.GetStdout(), "Using "+c.tool+" at '"+c.executable+"' to generate a wrapper at '"+c.rootDir+"'")
+	}
+
+	cmd := exec.Command(c.executable, args...)
+	cmd.Dir = c.rootDir
+	cmd.Env = c.java.environment(c.context)
+	cmd.Stdout = c.context.GetStdout()
+	cmd.Stderr = c.context.GetStderr()
+	err := cmd.Run()
+	if err != nil {
+		var exerr *exec.ExitError
+		if errors.As(err, &exerr) {
+			return exerr.ExitCode()
+		}
+		fmt.Fprintln(c.context.GetStdout(), err)
+		return -1
+	}
+
+	c.writeChecksum()
+
+	// the wrapper was generated at the user's request
+	wrapper := c.resolveWrapper()
+	checksum, err := sha256File(wrapper)
+	if err == nil {
+		allowTrustedFiles(c.context, map[string]string{wrapper: checksum})
+	}
+
+	return 0
+}
+
+// Resolves the arguments of the wrapper task (Gradle) or goal (Maven), offline
+func (c *WrapperCommand) resolveToolArgs() []string {
+	args := make([]string, 0)
+	if c.tool == "gradle" {
+		args = append(args, "--offline", "wrapper")
+		if len(c.version.expected) > 0 {
+			args = append(args, "--gradle-version", c.version.expected)
+		}
+	} else {
+		args = append(args, "--offline", "--non-recursive", "wrapper:wrapper")
+		if len(c.version.expected) > 0 {
+			args = append(args, "-Dmaven="+c.version.expected)
+		}
+	}
+	return appendSafe(args, c.args.Tool)
+}
+
+func (c *WrapperCommand) resolveWrapper() string {
+	if c.tool == "gradle" {
+		return filepath.Join(c.rootDir, resolveGradleWrapperExec(c.context))
+	}
+	return filepath.Join(c.rootDir, resolveMavenWrapperExec(c.context))
+}
+
+func (c *WrapperCommand) resolveProperties() string {
+	if c.tool == "gradle" {
+		return filepath.Join(c.rootDir, "gradle", "wrapper", "gradle-wrapper.properties")
+	}
+	return filepath.Join(c.rootDir, ".mvn", "wrapper", "maven-wrapper.properties")
+}
+
+// Resolves the locations where a distribution downloaded from url may be cached locally
+func (c *WrapperCommand) resolveDistributionCandidates(url string) []string {
+	zip := filepath.Base(url)
+	name := strings.TrimSuffix(zip, ".zip")
+
+	if c.tool == "gradle" {
+		gradleUserHome := c.context.GetEnv("GRADLE_USER_HOME")
+		if len(gradleUserHome) == 0 {
+			gradleUserHome = filepath.Join(c.context.GetHomeDir(), ".gradle")
+		}
+		return []string{filepath.Join(gradleUserHome, "wrapper", "dists", name, "*", zip)}
+	}
+
+	mavenUserHome := c.context.GetEnv("MAVEN_USER_HOME")
+	if len(mavenUserHome) == 0 {
+		mavenUserHome = filepath.Join(c.context.GetHomeDir(), ".m2")
+	}
+	candidates := []string{filepath.Join(mavenUserHome, "wrapper", "dists", name, "*", zip)}
+	matches := mavenDistributionVersionPattern.FindStringSubmatch(zip)
+	if matches != nil {
+		candidates = append(candidates, filepath.Join(c.context.GetHomeDir(), ".m2", "repository",
+			"org", "apache", "maven", "apache-maven", matches[1], zip))
+	}
+	return candidates
+}
+
+// Writes distributionSha256Sum to the wrapper properties when the distribution is found locally
+func (c *WrapperCommand) writeChecksum() {
+	file := c.resolveProperties()
+	properties := readProperties(file)
+	url := properties["distributionUrl"]
+	if len(url) == 0 {
+		return
+	}
+
+	if sum := properties["distributionSha256Sum"]; len(sum) > 0 {
+		c.report("distributionSha256Sum " + sum + " already set in " + file)
+		return
+	}
+
+	for _, candidate := range c.resolveDistributionCandidates(url) {
+		matches, _ := filepath.Glob(candidate)
+		for _, match := range matches {
+			checksum, err := sha256File(match)
+			if err != nil {
+				continue
+			}
+			if err := appendProperty(file, "distributionSha256Sum", checksum); err != nil {
+				fmt.Fprintln(c.context.GetStdout(), err)
+				return
+			}
+			c.report("Wrote distributionSha256Sum " + checksum + " (" + match + ") to " + file)
+			return
+		}
+	}
+
+	c.report("Did not find " + filepath.Base(url) + " locally, distributionSha256Sum was not written")
+}
+
+func (c *WrapperCommand) report(message string) {
+	if !c.config.general.quiet {
+		fmt.Fprintln(c.context.GetStdout(), message)
+	}
+}
+
+// Appends key=value to the given properties file
+func appendProperty(file string, key string, value string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	content := string(data)
+	if len(content) > 0 && !strings.HasSuffix(content, "\n") {
+		content = content + "\n"
+	}
+	return os.WriteFile(file, []byte(content+key+"="+value+"\n"), 0644)
+}
+
+// FindWrapper finds the project at the current directory and the build tool that generates its wrapper
+func FindWrapper(context Context, args *ParsedArgs) *WrapperCommand {
+	pwd := context.GetWorkingDir()
+
+	tool, rootdir := resolveWrapperProject(context, args, pwd)
+	if len(tool) == 0 {
+		fmt.Fprintln(context.GetStdout(), "Did not find a Gradle nor a Maven project")
+		context.Exit(-1)
+		return nil
+	}
+
+	config := ReadConfig(context, rootdir)
+	if args.HasGumFlag("gq") {
+		config.setQuiet(true)
+	}
+	if args.HasGumFlag("gd") {
+		config.setDebug(true)
+	}
+
+	var wrapper, executable string
+	var version *toolVersion
+	var noWrapper, noExecutable error
+	executable, noExecutable = findWrapperToolExec(context, config, tool)
+	if tool == "gradle" {
+		wrapper, noWrapper = findGradleWrapperExec(context, rootdir)
+		version = findExpectedToolVersion(context, rootdir, filepath.Join("gradle", "wrapper", "gradle-wrapper.properties"), gradleDistributionVersionPattern, "gradle", config.gradle.version)
+	} else {
+		wrapper, noWrapper = findMavenWrapperExec(context, rootdir)
+		version = findExpectedToolVersion(context, rootdir, filepath.Join(".mvn", "wrapper", "maven-wrapper.properties"), mavenDistributionVersionPattern, "maven", config.maven.version)
+	}
+
+	if noWrapper == nil {
+		fmt.Fprintln(context.GetStdout(), "A "+tool+" wrapper is already set up at '"+wrapper+"'")
+		if len(version.expected) > 0 {
+			fmt.Fprintln(context.GetStdout(), "version = "+version.String())
+		}
+		context.Exit(0)
+		return nil
+	}
+
+	if noExecutable != nil {
+		fmt.Fprintln(context.GetStdout(), "Did not find "+tool+" to generate a wrapper with")
+		context.Exit(-1)
+		return nil
+	}
+
+	if len(args.Args) > 0 {
+		version = &toolVersion{expected: args.Args[0], source: "command line"}
+	}
+
+	return &WrapperCommand{
+		context:    context,
+		config:     config,
+		args:       args,
+		tool:       tool,
+		executable: executable,
+		rootDir:    rootdir,
+		version:    version}
+}
+
+// Finds the build tool executable that generates the wrapper
+func findWrapperToolExec(context Context, config *Config, tool string) (string, error) {
+	if tool == "gradle" {
+		executable, _, err := findGradleExec(context, config)
+		return executable, err
+	}
+	executable, _, err := findMavenExec(context, config)
+	return executable, err
+}
+
+// Resolves the build tool and root directory of the project at the given directory.
+// Gradle is preferred over Maven unless -gm is given.
+func resolveWrapperProject(context Context, args *ParsedArgs, pwd string) (string, string) {
+	if !args.HasGumFlag("gm") {
+		settingsFile, noSettings := findGradleSettingsFile(context, pwd)
+		if noSettings == nil {
+			return "gradle", filepath.Dir(settingsFile)
+		}
+		buildFile, noBuildFile := findGradleBuildFile(context, pwd)
+		if noBuildFile == nil {
+			return "gradle", filepath.Dir(buildFile)
+		}
+	}
+
+	if !args.HasGumFlag("gg") {
+		buildFile, noBuildFile := findMavenBuildFile(context, pwd)
+		if noBuildFile == nil {
+			rootBuildFile, _, noRootBuildFile := findMavenRootFile(context, buildFile)
+			if noRootBuildFile == nil {
+				return "maven", filepath.Dir(rootBuildFile)
+			}
+			return "maven", filepath.Dir(buildFile)
+		}
+	}
+
+	return "", ""
+}
@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWrapperBootstrapGradle(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "single-without-wrapper"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gw", "--distribution-type", "all", "8.10.2"})
+	cmd := FindWrapper(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Tool", cmd.tool, "gradle"},
+		{"Executable", cmd.executable, filepath.Join(bin, "gradle")},
+		{"RootDir", cmd.rootDir, pwd},
+		{"Args", strings.Join(cmd.resolveToolArgs(), " "), "--offline wrapper --gradle-version 8.10.2 --distribution-type all"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestWrapperBootstrapMaven(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "single-without-wrapper"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gw", "3.9.9"})
+	cmd := FindWrapper(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Tool", cmd.tool, "maven"},
+		{"Executable", cmd.executable, filepath.Join(bin, "mvn")},
+		{"Args", strings.Join(cmd.resolveToolArgs(), " "), "--offline --non-recursive wrapper:wrapper -Dmaven=3.9.9"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestWrapperBootstrapWithExistingWrapper(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "single-with-wrapper"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gw"})
+	cmd := FindWrapper(context, &args)
+
+	// then:
+	if cmd != nil {
+		t.Error("Expected nil but got a command")
+	}
+}
+
+func TestWrapperBootstrapWritesChecksum(t *testing.T) {
+	// given:
+	home, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "wrapper-verify", "home"))
+	pwd := t.TempDir()
+	properties := filepath.Join(pwd, "gradle", "wrapper", "gradle-wrapper.properties")
+	os.MkdirAll(filepath.Dir(properties), 0755)
+	os.WriteFile(properties, []byte("distributionUrl=https\\://services.gradle.org/distributions/gradle-8.10.2-bin.zip"), 0644)
+
+	config := newConfig()
+	config.setQuiet(true)
+	cmd := WrapperCommand{
+		context: testContext{workingDir: pwd, homeDir: home},
+		config:  config,
+		tool:    "gradle",
+		rootDir: pwd}
+
+	// when:
+	cmd.writeChecksum()
+
+	// then:
+	expected, _ := sha256File(filepath.Join(home, ".gradle", "wrapper", "dists", "gradle-8.10.2-bin", "abc123", "gradle-8.10.2-bin.zip"))
+	actual := readProperties(properties)["distributionSha256Sum"]
+	if actual != expected {
+		t.Errorf("distributionSha256Sum: got %s, want %s", actual, expected)
+	}
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// searchCeilings caches [general] searchCeiling of the user config, keyed by home directory
+var searchCeilings sync.Map
+
+// Resolves the directory an upward search continues with once dir has been searched.
+// Returns false when dir is the last directory to search, that is when dir
+// - is the root of a git or mercurial repository
+// - holds a .gm-root marker file
+// - is the home directory or a ceiling directory, or its parent is one of them
+// - its parent is found on a different mount
+// Ceiling directories are read from GM_CEILING_DIRECTORIES and [general] searchCeiling.
+func resolveSearchParent(context Context, dir string) (string, bool) {
+	dir, _ = filepath.Abs(dir)
+	parentdir := filepath.Dir(dir)
+	if parentdir == dir {
+		return dir, false
+	}
+
+	for _, marker := range []string{".git", ".hg", ".gm-root"} {
+		if context.FileExists(filepath.Join(dir, marker)) {
+			return dir, false
+		}
+	}
+
+	for _, ceiling := range resolveSearchCeilings(context) {
+		if dir == ceiling || parentdir == ceiling {
+			return dir, false
+		}
+	}
+
+	if isMountPoint(dir, parentdir) {
+		return dir, false
+	}
+
+	return parentdir, true
+}
+
+// Resolves the home directory, GM_CEILING_DIRECTORIES, and [general] searchCeiling as absolute paths
+func resolveSearchCeilings(context Context) []string {
+	ceilings := make([]string, 0)
+
+	home := context.GetHomeDir()
+	if context.IsWindows() {
+		home = context.GetEnv("USERPROFILE")
+	}
+	if len(home) > 0 {
+		ceilings = append(ceilings, home)
+	}
+
+	for _, ceiling := range strings.Split(context.GetEnv("GM_CEILING_DIRECTORIES"), string(os.PathListSeparator)) {
+		if len(strings.TrimSpace(ceiling)) > 0 {
+			ceilings = append(ceilings, ceiling)
+		}
+	}
+
+	configured, ok := searchCeilings.Load(home)
+	if !ok {
+		configured = ReadUserConfig(context).general.searchCeiling
+		searchCeilings.Store(home, configured)
+	}
+	ceilings = append(ceilings, configured.([]string)...)
+
+	for i, ceiling := range ceilings {
+		if (strings.HasPrefix(ceiling, "~/") || strings.HasPrefix(ceiling, "~"+string(os.PathSeparator))) && len(home) > 0 {
+			ceiling = filepath.Join(home, ceiling[2:])
+		}
+		ceilings[i], _ = filepath.Abs(ceiling)
+	}
+
+	return ceilings
+}
+
+// Checks if dir and parentdir are found on different devices
+func isMountPoint(dir string, parentdir string) bool {
+	device, ok := resolveDevice(dir)
+	if !ok {
+		return false
+	}
+	parentDevice, ok := resolveDevice(parentdir)
+	return ok && device != parentDevice
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSearchBoundary(t *testing.T) {
+	var checks = []struct {
+		title    string
+		marker   string
+		env      string
+		ceiling  string
+		expected string
+	}{
+		{"NoBoundary", "", "", "", "build.xml"},
+		{"GitRoot", ".git", "", "", ""},
+		{"MercurialRoot", ".hg", "", "", ""},
+		{"GmRoot", ".gm-root", "", "", ""},
+		{"CeilingFromEnv", "", "a", "", ""},
+		{"CeilingFromConfig", "", "", "a", ""},
+		{"CeilingBelow", "", "", "a/b", ""},
+	}
+
+	for _, check := range checks {
+		t.Run(check.title, func(t *testing.T) {
+			// given:
+			home := t.TempDir()
+			root := t.TempDir()
+			pwd := filepath.Join(root, "a", "b", "c")
+			os.MkdirAll(pwd, 0755)
+			os.WriteFile(filepath.Join(root, "build.xml"), []byte(""), 0644)
+			os.WriteFile(filepath.Join(root, "jbang"), []byte(""), 0755)
+			if len(check.marker) > 0 {
+				os.Mkdir(filepath.Join(root, "a", check.marker), 0755)
+			}
+			env := make(map[string]string)
+			if len(check.env) > 0 {
+				env["GM_CEILING_DIRECTORIES"] = filepath.Join(root, check.env)
+			}
+			if len(check.ceiling) > 0 {
+				os.WriteFile(filepath.Join(home, ".gm.toml"), []byte("[general]\nsearchCeiling = [\""+filepath.ToSlash(filepath.Join(root, check.ceiling))+"\"]\n"), 0644)
+			}
+
+			context := testContext{
+				quiet:      true,
+				workingDir: pwd,
+				homeDir:    home,
+				env:        env}
+
+			// when:
+			buildFile, _ := findAntBuildFile(context, pwd)
+			jbangw, _ := findJbangWrapperExec(context, pwd)
+
+			// then:
+			expected := ""
+			expectedJbangw := ""
+			if len(check.expected) > 0 {
+				expected = filepath.Join(root, check.expected)
+				expectedJbangw = filepath.Join(root, "jbang")
+			}
+			if buildFile != expected {
+				t.Errorf("%s: got %s want %s", check.title, buildFile, expected)
+			}
+			if jbangw != expectedJbangw {
+				t.Errorf("%s jbang: got %s want %s", check.title, jbangw, expectedJbangw)
+			}
+		})
+	}
+}
+
+func TestSearchBoundaryKeepsRepositoryRoot(t *testing.T) {
+	// given:
+	root := t.TempDir()
+	pwd := filepath.Join(root, "module")
+	os.MkdirAll(pwd, 0755)
+	os.Mkdir(filepath.Join(root, ".git"), 0755)
+	os.WriteFile(filepath.Join(root, "pom.xml"), []byte(""), 0644)
+
+	context := testContext{
+		quiet:      true,
+		workingDir: pwd,
+		homeDir:    t.TempDir()}
+
+	// when:
+	buildFile, err := findMavenBuildFile(context, pwd)
+
+	// then:
+	if err != nil || buildFile != filepath.Join(root, "pom.xml") {
+		t.Errorf("buildFile: got %s want %s", buildFile, filepath.Join(root, "pom.xml"))
+	}
+}
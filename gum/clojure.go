@@ -0,0 +1,307 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ClojureCommand defines an executable Leiningen or Clojure CLI command
+type ClojureCommand struct {
+	context    Context
+	config     *Config
+	rootdir    string
+	executable string
+	args       *ParsedArgs
+	buildFile  string
+	lein       bool
+}
+
+// Execute executes the given command
+func (c ClojureCommand) Execute() int {
+	if !trustProject(c.context, c.config) {
+		c.context.Exit(-1)
+	}
+	c.doConfigureClojure()
+	return c.doExecuteClojure()
+}
+
+func (c *ClojureCommand) doConfigureClojure() {
+	c.context.CheckIsExecutable(c.executable)
+
+	args := make([]string, 0)
+
+	banner := make([]string, 0)
+	if c.lein {
+		banner = append(banner, "Using lein at '"+c.executable+"'")
+	} else {
+		banner = append(banner, "Using clojure at '"+c.executable+"'")
+	}
+	banner = append(banner, "to run buildFile '"+c.buildFile+"':")
+	debug := c.args.HasGumFlag("gd")
+	skipReplace := c.args.HasGumFlag("gr")
+
+	if debug {
+		c.config.setDebug(debug)
+	}
+	if skipReplace {
+		c.config.clojure.setReplace(!skipReplace)
+	}
+	c.debugConfig()
+	otargs := c.args.Tool
+	oargs := c.args.Args
+	rargs := replaceClojureGoals(c.config, c.args, c.lein)
+
+	args = appendSafe(args, c.args.Tool)
+	c.args.Args = appendSafe(args, rargs)
+
+	c.debugClojure(otargs, oargs, rargs)
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(c.context.GetStdout(), strings.Join(banner, " "))
+	}
+}
+
+func (c *ClojureCommand) doExecuteClojure() int {
+	cmd := exec.Command(c.executable, c.args.Args...)
+	cmd.Dir = c.rootdir
+	cmd.Stdout = c.context.GetStdout()
+	cmd.Stderr = c.context.GetStderr()
+	cmd.Stdin = os.Stdin
+	err := cmd.Run()
+	var exerr *exec.ExitError
+	if errors.As(err, &exerr) {
+		return exerr.ExitCode()
+	}
+	if err != nil {
+		fmt.Fprintln(c.context.GetStderr(), err)
+		return -1
+	}
+	return 0
+}
+
+func (c *ClojureCommand) debugConfig() {
+	if c.args.HasGumFlag("gc") {
+		c.config.print(c.context.GetStdout())
+		c.context.Exit(0)
+	}
+}
+
+func (c *ClojureCommand) debugClojure(otargs []string, oargs []string, rargs []string) {
+	if c.config.general.debug {
+		fmt.Fprintln(c.context.GetStdout(), "replace            = ", c.config.clojure.replace)
+		fmt.Fprintln(c.context.GetStdout(), "pwd                = ", c.context.GetWorkingDir())
+		fmt.Fprintln(c.context.GetStdout(), "rootdir            = ", c.rootdir)
+		fmt.Fprintln(c.context.GetStdout(), "executable         = ", c.executable)
+		fmt.Fprintln(c.context.GetStdout(), "buildFile          = ", c.buildFile)
+		fmt.Fprintln(c.context.GetStdout(), "original tool args = ", otargs)
+		fmt.Fprintln(c.context.GetStdout(), "original args      = ", oargs)
+		if c.config.clojure.replace {
+			fmt.Fprintln(c.context.GetStdout(), "replaced args      = ", rargs)
+		}
+		fmt.Fprintln(c.context.GetStdout(), "actual args        = ", c.args.Args)
+		fmt.Fprintln(c.context.GetStdout(), "")
+	}
+}
+
+// Replaces lifecycle goals with Leiningen tasks or Clojure CLI invocations.
+// A mapping value may expand into several arguments, i.e, "-T:build uber".
+func replaceClojureGoals(config *Config, args *ParsedArgs, lein bool) []string {
+	if !config.clojure.replace {
+		return args.Args
+	}
+
+	mappings := config.clojure.deps
+	if lein {
+		mappings = config.clojure.lein
+	}
+
+	// a replacement may expand to several arguments, user arguments are passed as is
+	nargs := make([]string, 0)
+	for _, arg := range args.Args {
+		if replacement, ok := mappings[arg]; ok && len(replacement) > 0 {
+			nargs = append(nargs, strings.Fields(replacement)...)
+		} else {
+			nargs = append(nargs, arg)
+		}
+	}
+	return nargs
+}
+
+// FindClojure finds and executes lein/clojure
+func FindClojure(context Context, args *ParsedArgs) *ClojureCommand {
+	pwd := context.GetWorkingDir()
+
+	buildFile, noBuildFile := findClojureBuildFile(context, pwd)
+	rootdir := filepath.Dir(buildFile)
+	config := ReadConfig(context, rootdir)
+	quiet := args.HasGumFlag("gq")
+
+	if quiet {
+		config.setQuiet(quiet)
+	}
+
+	if noBuildFile != nil {
+		if context.IsExplicit() {
+			fmt.Fprintln(context.GetStdout(), "No Leiningen nor Clojure CLI project found")
+			fmt.Fprintln(context.GetStdout())
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	lein := filepath.Base(buildFile) == "project.clj"
+
+	var executable string
+	if lein {
+		leinExec, noLein := findLeinExec(context)
+		if noLein != nil {
+			warnNoLein(context, config)
+
+			if context.IsExplicit() {
+				context.Exit(-1)
+			}
+			return nil
+		}
+		executable = leinExec
+	} else {
+		clojureExec, noClojure := findClojureExec(context)
+		if noClojure != nil {
+			warnNoClojure(context, config)
+
+			if context.IsExplicit() {
+				context.Exit(-1)
+			}
+			return nil
+		}
+		executable = clojureExec
+	}
+
+	return &ClojureCommand{
+		context:    context,
+		config:     config,
+		rootdir:    rootdir,
+		executable: executable,
+		args:       args,
+		buildFile:  buildFile,
+		lein:       lein}
+}
+
+func warnNoLein(context Context, config *Config) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(context.GetStdout(), "No %s found in path. Please install Leiningen.", resolveLeinExec(context))
+		fmt.Fprintln(context.GetStdout())
+		fmt.Fprintln(context.GetStdout(), "(https://leiningen.org/#install)")
+		fmt.Fprintln(context.GetStdout())
+	}
+}
+
+func warnNoClojure(context Context, config *Config) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(context.GetStdout(), "No %s found in path. Please install the Clojure CLI.", resolveClojureExec(context))
+		fmt.Fprintln(context.GetStdout())
+		fmt.Fprintln(context.GetStdout(), "(https://clojure.org/guides/install_clojure)")
+		fmt.Fprintln(context.GetStdout())
+	}
+}
+
+// Finds the lein executable
+func findLeinExec(context Context) (string, error) {
+	lein := resolveLeinExec(context)
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], lein)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New(lein + " not found")
+}
+
+// Finds the clojure executable, falling back to clj
+func findClojureExec(context Context) (string, error) {
+	var clojure [2]string
+	clojure[0] = resolveClojureExec(context)
+	clojure[1] = resolveCljExec(context)
+	paths := context.GetPaths()
+
+	for j := range clojure {
+		for i := range paths {
+			name := filepath.Join(paths[i], clojure[j])
+			if context.FileExists(name) {
+				return filepath.Abs(name)
+			}
+		}
+	}
+
+	return "", errors.New(clojure[0] + " not found")
+}
+
+// Finds the nearest Clojure build file
+// Checks the following paths in order:
+// - project.clj
+// - deps.edn
+func findClojureBuildFile(context Context, dir string) (string, error) {
+	var buildFiles [2]string
+	buildFiles[0] = "project.clj"
+	buildFiles[1] = "deps.edn"
+
+	for i := range buildFiles {
+		path := filepath.Join(dir, buildFiles[i])
+		if context.FileExists(path) {
+			return filepath.Abs(path)
+		}
+	}
+
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New("Did not find project.clj nor deps.edn")
+	}
+
+	return findClojureBuildFile(context, parentdir)
+}
+
+// Resolves the lein executable (OS dependent)
+func resolveLeinExec(context Context) string {
+	if context.IsWindows() {
+		return "lein.bat"
+	}
+	return "lein"
+}
+
+// Resolves the clojure executable (OS dependent)
+func resolveClojureExec(context Context) string {
+	if context.IsWindows() {
+		return "clojure.exe"
+	}
+	return "clojure"
+}
+
+// Resolves the clj executable (OS dependent)
+func resolveCljExec(context Context) string {
+	if context.IsWindows() {
+		return "clj.exe"
+	}
+	return "clj"
+}
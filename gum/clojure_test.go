@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClojureLein(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "clojure", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "clojure", "lein"))
+	pwd := filepath.Join(root, "src")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "build"})
+	cmd := FindClojure(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "lein")},
+		{"RootDir", cmd.rootdir, root},
+		{"BuildFile", cmd.buildFile, filepath.Join(root, "project.clj")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+
+	cmd.doConfigureClojure()
+	if strings.Join(cmd.args.Args, " ") != "uberjar" {
+		t.Errorf("args: got %s, want [uberjar]", cmd.args.Args)
+	}
+}
+
+func TestClojureDeps(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "clojure", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "clojure", "deps"))
+	pwd := filepath.Join(root, "src")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "test"})
+	cmd := FindClojure(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "clojure")},
+		{"RootDir", cmd.rootdir, root},
+		{"BuildFile", cmd.buildFile, filepath.Join(root, "deps.edn")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+
+	cmd.doConfigureClojure()
+	if strings.Join(cmd.args.Args, " ") != "-X:test" {
+		t.Errorf("args: got %s, want [-X:test]", cmd.args.Args)
+	}
+}
+
+func TestClojureDepsBuildExpandsArgs(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "clojure", "clj-bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "clojure", "deps"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "build"})
+	cmd := FindClojure(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	if cmd.executable != filepath.Join(bin, "clj") {
+		t.Errorf("Executable: got %s, want %s", cmd.executable, filepath.Join(bin, "clj"))
+	}
+
+	cmd.doConfigureClojure()
+	if len(cmd.args.Args) != 2 || cmd.args.Args[0] != "-T:build" || cmd.args.Args[1] != "uber" {
+		t.Errorf("args: got %s, want [-T:build uber]", cmd.args.Args)
+	}
+}
+
+func TestClojureWithoutExecutables(t *testing.T) {
+	// given:
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "clojure", "lein"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindClojure(context, &args)
+
+	// then:
+	if cmd != nil {
+		t.Error("Expected a nil command but got something")
+	}
+}
+
+func TestClojureKeepsUserArgs(t *testing.T) {
+	// given:
+	config := newConfig()
+	config.merge(nil)
+	args := &ParsedArgs{Args: []string{"build", "-M:run", "hello world"}}
+
+	// when:
+	nargs := replaceClojureGoals(config, args, false)
+
+	// then:
+	expected := []string{"-T:build", "uber", "-M:run", "hello world"}
+	if len(nargs) != len(expected) {
+		t.Errorf("args: got %q, want %q", nargs, expected)
+		return
+	}
+	for i := range expected {
+		if nargs[i] != expected[i] {
+			t.Errorf("args: got %q, want %q", nargs, expected)
+		}
+	}
+}
@@ -18,6 +18,7 @@ package gum
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"path/filepath"
 	"strings"
@@ -33,8 +34,30 @@ type Config struct {
 	general general
 	gradle  gradle
 	maven   maven
+	ant     ant
 	jbang   jbang
 	bach    bach
+	sbt     sbt
+	mill    mill
+	bazel   bazel
+	clojure clojure
+	scripts scripts
+	amper   amper
+	java    java
+
+	// project config file, if any
+	projectFile string
+	// project settings withheld until the project config is trusted
+	withheld *withheldSettings
+}
+
+// Project settings that run or select executables
+type withheldSettings struct {
+	gradleExecutable string
+	mavenExecutable  string
+	antExecutable    string
+	jbangExecutable  string
+	java             java
 }
 
 type theme struct {
@@ -49,28 +72,46 @@ type theme struct {
 }
 
 type general struct {
-	quiet     bool
-	debug     bool
-	discovery []string
+	quiet         bool
+	debug         bool
+	discovery     []string
+	trust         string
+	tool          string
+	scanExclude   []string
+	searchCeiling []string
 
 	q tribool.Tribool
 	d tribool.Tribool
 }
 
 type gradle struct {
-	replace  bool
-	defaults bool
-	mappings map[string]string
+	replace          bool
+	defaults         bool
+	scope            bool
+	executable       string
+	version          string
+	mismatch         string
+	verifyWrapper    string
+	wrapperChecksums string
+	mappings         map[string]string
 
 	r tribool.Tribool
 	d tribool.Tribool
+	s tribool.Tribool
 }
 
 type maven struct {
-	replace  bool
-	defaults bool
-	mvnd     bool
-	mappings map[string]string
+	replace       bool
+	defaults      bool
+	mvnd          bool
+	scope         string
+	executable    string
+	version       string
+	mismatch      string
+	verifyWrapper string
+	wrapperHosts  []string
+	mvndExclude   []string
+	mappings      map[string]string
 
 	r tribool.Tribool
 	d tribool.Tribool
@@ -78,6 +119,24 @@ type maven struct {
 }
 
 type jbang struct {
+	discovery  []string
+	fallback   bool
+	executable string
+
+	f tribool.Tribool
+}
+
+type ant struct {
+	executable string
+}
+
+type java struct {
+	version     string
+	directories []string
+	mismatch    string
+}
+
+type scripts struct {
 	discovery []string
 }
 
@@ -85,39 +144,151 @@ type bach struct {
 	version string
 }
 
-func (c *Config) print() {
-	c.theme.t.PrintSection("theme")
-	c.theme.t.PrintKeyValueLiteral("name", c.theme.name)
+type sbt struct {
+	replace  bool
+	defaults bool
+	mappings map[string]string
+
+	r tribool.Tribool
+	d tribool.Tribool
+}
+
+type mill struct {
+	replace  bool
+	defaults bool
+	mappings map[string]string
+
+	r tribool.Tribool
+	d tribool.Tribool
+}
+
+type bazel struct {
+	replace  bool
+	defaults bool
+	mappings map[string]string
+
+	r tribool.Tribool
+	d tribool.Tribool
+}
+
+type amper struct {
+	replace  bool
+	defaults bool
+	mappings map[string]string
+
+	r tribool.Tribool
+	d tribool.Tribool
+}
+
+type clojure struct {
+	replace  bool
+	defaults bool
+	lein     map[string]string
+	deps     map[string]string
+
+	r tribool.Tribool
+	d tribool.Tribool
+}
+
+func (c *Config) print(out io.Writer) {
+	c.theme.t.PrintSection(out, "theme")
+	c.theme.t.PrintKeyValueLiteral(out, "name", c.theme.name)
 	if isInstanceOf(c.theme.t, (*ColoredTheme)(nil)) {
-		c.theme.t.PrintKeyValueArrayI("symbol", c.theme.symbol)
-		c.theme.t.PrintKeyValueArrayI("section", c.theme.section)
-		c.theme.t.PrintKeyValueArrayI("key", c.theme.key)
-		c.theme.t.PrintKeyValueArrayI("boolean", c.theme.boolean)
-		c.theme.t.PrintKeyValueArrayI("literal", c.theme.literal)
-	}
-	c.theme.t.PrintSection("general")
-	c.theme.t.PrintKeyValueBoolean("quiet", c.general.quiet)
-	c.theme.t.PrintKeyValueBoolean("debug", c.general.debug)
-	c.theme.t.PrintKeyValueArrayS("discovery", c.general.discovery)
-	c.theme.t.PrintSection("gradle")
-	c.theme.t.PrintKeyValueBoolean("replace", c.gradle.replace)
-	c.theme.t.PrintKeyValueBoolean("defaults", c.gradle.defaults)
+		c.theme.t.PrintKeyValueArrayI(out, "symbol", c.theme.symbol)
+		c.theme.t.PrintKeyValueArrayI(out, "section", c.theme.section)
+		c.theme.t.PrintKeyValueArrayI(out, "key", c.theme.key)
+		c.theme.t.PrintKeyValueArrayI(out, "boolean", c.theme.boolean)
+		c.theme.t.PrintKeyValueArrayI(out, "literal", c.theme.literal)
+	}
+	c.theme.t.PrintSection(out, "general")
+	c.theme.t.PrintKeyValueBoolean(out, "quiet", c.general.quiet)
+	c.theme.t.PrintKeyValueBoolean(out, "debug", c.general.debug)
+	c.theme.t.PrintKeyValueArrayS(out, "discovery", c.general.discovery)
+	c.theme.t.PrintKeyValueLiteral(out, "trust", c.general.trust)
+	c.theme.t.PrintKeyValueLiteral(out, "tool", c.general.tool)
+	c.theme.t.PrintKeyValueArrayS(out, "scanExclude", c.general.scanExclude)
+	c.theme.t.PrintKeyValueArrayS(out, "searchCeiling", c.general.searchCeiling)
+	c.theme.t.PrintSection(out, "gradle")
+	c.theme.t.PrintKeyValueBoolean(out, "replace", c.gradle.replace)
+	c.theme.t.PrintKeyValueBoolean(out, "defaults", c.gradle.defaults)
+	c.theme.t.PrintKeyValueBoolean(out, "scope", c.gradle.scope)
+	c.theme.t.PrintKeyValueLiteral(out, "executable", c.gradle.executable)
+	c.theme.t.PrintKeyValueLiteral(out, "version", c.gradle.version)
+	c.theme.t.PrintKeyValueLiteral(out, "mismatch", c.gradle.mismatch)
+	c.theme.t.PrintKeyValueLiteral(out, "verifyWrapper", c.gradle.verifyWrapper)
+	c.theme.t.PrintKeyValueLiteral(out, "wrapperChecksums", c.gradle.wrapperChecksums)
 	if len(c.gradle.mappings) > 0 {
-		c.theme.t.PrintSection("gradle.mappings")
-		c.theme.t.PrintMap(c.gradle.mappings)
+		c.theme.t.PrintSection(out, "gradle.mappings")
+		c.theme.t.PrintMap(out, c.gradle.mappings)
 	}
-	c.theme.t.PrintSection("maven")
-	c.theme.t.PrintKeyValueBoolean("replace", c.maven.replace)
-	c.theme.t.PrintKeyValueBoolean("defaults", c.maven.defaults)
-	c.theme.t.PrintKeyValueBoolean("mvnd", c.maven.mvnd)
+	c.theme.t.PrintSection(out, "maven")
+	c.theme.t.PrintKeyValueBoolean(out, "replace", c.maven.replace)
+	c.theme.t.PrintKeyValueBoolean(out, "defaults", c.maven.defaults)
+	c.theme.t.PrintKeyValueBoolean(out, "mvnd", c.maven.mvnd)
+	c.theme.t.PrintKeyValueLiteral(out, "scope", c.maven.scope)
+	c.theme.t.PrintKeyValueLiteral(out, "executable", c.maven.executable)
+	c.theme.t.PrintKeyValueLiteral(out, "version", c.maven.version)
+	c.theme.t.PrintKeyValueLiteral(out, "mismatch", c.maven.mismatch)
+	c.theme.t.PrintKeyValueLiteral(out, "verifyWrapper", c.maven.verifyWrapper)
+	c.theme.t.PrintKeyValueArrayS(out, "wrapperHosts", c.maven.wrapperHosts)
+	c.theme.t.PrintKeyValueArrayS(out, "mvndExclude", c.maven.mvndExclude)
 	if len(c.maven.mappings) > 0 {
-		c.theme.t.PrintSection("maven.mappings")
-		c.theme.t.PrintMap(c.maven.mappings)
+		c.theme.t.PrintSection(out, "maven.mappings")
+		c.theme.t.PrintMap(out, c.maven.mappings)
+	}
+	c.theme.t.PrintSection(out, "jbang")
+	c.theme.t.PrintKeyValueArrayS(out, "discovery", c.jbang.discovery)
+	c.theme.t.PrintKeyValueBoolean(out, "fallback", c.jbang.fallback)
+	c.theme.t.PrintKeyValueLiteral(out, "executable", c.jbang.executable)
+	c.theme.t.PrintSection(out, "ant")
+	c.theme.t.PrintKeyValueLiteral(out, "executable", c.ant.executable)
+	c.theme.t.PrintSection(out, "java")
+	c.theme.t.PrintKeyValueLiteral(out, "version", c.java.version)
+	c.theme.t.PrintKeyValueArrayS(out, "directories", c.java.directories)
+	c.theme.t.PrintKeyValueLiteral(out, "mismatch", c.java.mismatch)
+	c.theme.t.PrintSection(out, "scripts")
+	c.theme.t.PrintKeyValueArrayS(out, "discovery", c.scripts.discovery)
+	c.theme.t.PrintSection(out, "bach")
+	c.theme.t.PrintKeyValueLiteral(out, "version", c.bach.version)
+	c.theme.t.PrintSection(out, "sbt")
+	c.theme.t.PrintKeyValueBoolean(out, "replace", c.sbt.replace)
+	c.theme.t.PrintKeyValueBoolean(out, "defaults", c.sbt.defaults)
+	if len(c.sbt.mappings) > 0 {
+		c.theme.t.PrintSection(out, "sbt.mappings")
+		c.theme.t.PrintMap(out, c.sbt.mappings)
+	}
+	c.theme.t.PrintSection(out, "mill")
+	c.theme.t.PrintKeyValueBoolean(out, "replace", c.mill.replace)
+	c.theme.t.PrintKeyValueBoolean(out, "defaults", c.mill.defaults)
+	if len(c.mill.mappings) > 0 {
+		c.theme.t.PrintSection(out, "mill.mappings")
+		c.theme.t.PrintMap(out, c.mill.mappings)
+	}
+	c.theme.t.PrintSection(out, "bazel")
+	c.theme.t.PrintKeyValueBoolean(out, "replace", c.bazel.replace)
+	c.theme.t.PrintKeyValueBoolean(out, "defaults", c.bazel.defaults)
+	if len(c.bazel.mappings) > 0 {
+		c.theme.t.PrintSection(out, "bazel.mappings")
+		c.theme.t.PrintMap(out, c.bazel.mappings)
+	}
+	c.theme.t.PrintSection(out, "clojure")
+	c.theme.t.PrintKeyValueBoolean(out, "replace", c.clojure.replace)
+	c.theme.t.PrintKeyValueBoolean(out, "defaults", c.clojure.defaults)
+	if len(c.clojure.lein) > 0 {
+		c.theme.t.PrintSection(out, "clojure.lein.mappings")
+		c.theme.t.PrintMap(out, c.clojure.lein)
+	}
+	if len(c.clojure.deps) > 0 {
+		c.theme.t.PrintSection(out, "clojure.deps.mappings")
+		c.theme.t.PrintMap(out, c.clojure.deps)
+	}
+	c.theme.t.PrintSection(out, "amper")
+	c.theme.t.PrintKeyValueBoolean(out, "replace", c.amper.replace)
+	c.theme.t.PrintKeyValueBoolean(out, "defaults", c.amper.defaults)
+	if len(c.amper.mappings) > 0 {
+		c.theme.t.PrintSection(out, "amper.mappings")
+		c.theme.t.PrintMap(out, c.amper.mappings)
 	}
-	c.theme.t.PrintSection("jbang")
-	c.theme.t.PrintKeyValueArrayS("discovery", c.jbang.discovery)
-	c.theme.t.PrintSection("bach")
-	c.theme.t.PrintKeyValueLiteral("version", c.bach.version)
 }
 
 func newConfig() *Config {
@@ -137,6 +308,7 @@ func newConfig() *Config {
 		gradle: gradle{
 			r:        tribool.Maybe,
 			d:        tribool.Maybe,
+			s:        tribool.Maybe,
 			mappings: make(map[string]string)},
 		maven: maven{
 			r:        tribool.Maybe,
@@ -144,9 +316,33 @@ func newConfig() *Config {
 			m:        tribool.Maybe,
 			mappings: make(map[string]string)},
 		jbang: jbang{
+			f:         tribool.Maybe,
+			discovery: make([]string, 0)},
+		scripts: scripts{
 			discovery: make([]string, 0)},
 		bach: bach{
-			version: ""}}
+			version: ""},
+		sbt: sbt{
+			r:        tribool.Maybe,
+			d:        tribool.Maybe,
+			mappings: make(map[string]string)},
+		mill: mill{
+			r:        tribool.Maybe,
+			d:        tribool.Maybe,
+			mappings: make(map[string]string)},
+		bazel: bazel{
+			r:        tribool.Maybe,
+			d:        tribool.Maybe,
+			mappings: make(map[string]string)},
+		clojure: clojure{
+			r:    tribool.Maybe,
+			d:    tribool.Maybe,
+			lein: make(map[string]string),
+			deps: make(map[string]string)},
+		amper: amper{
+			r:        tribool.Maybe,
+			d:        tribool.Maybe,
+			mappings: make(map[string]string)}}
 }
 
 func (c *Config) setQuiet(b bool) {
@@ -165,19 +361,83 @@ func (m *maven) setReplace(b bool) {
 	m.replace = b
 }
 
+func (m *maven) setScope(s string) {
+	m.scope = s
+}
+
+func (s *sbt) setReplace(b bool) {
+	s.replace = b
+}
+
+func (m *mill) setReplace(b bool) {
+	m.replace = b
+}
+
+func (b *bazel) setReplace(r bool) {
+	b.replace = r
+}
+
+func (c *clojure) setReplace(b bool) {
+	c.replace = b
+}
+
+func (a *amper) setReplace(b bool) {
+	a.replace = b
+}
+
+// Applies the project settings withheld by ReadConfig on top of the merged settings
+func (c *Config) restoreWithheld() {
+	w := c.withheld
+	if w == nil {
+		return
+	}
+	c.withheld = nil
+
+	if len(w.gradleExecutable) > 0 {
+		c.gradle.executable = w.gradleExecutable
+	}
+	if len(w.mavenExecutable) > 0 {
+		c.maven.executable = w.mavenExecutable
+	}
+	if len(w.antExecutable) > 0 {
+		c.ant.executable = w.antExecutable
+	}
+	if len(w.jbangExecutable) > 0 {
+		c.jbang.executable = w.jbangExecutable
+	}
+	w.java.merge(&c.java)
+	c.java = w.java
+}
+
 func (c *Config) merge(other *Config) {
 	if other == nil {
 		c.general.merge(nil)
 		c.gradle.merge(nil)
 		c.maven.merge(nil)
+		c.ant.merge(nil)
 		c.jbang.merge(nil)
 		c.bach.merge(nil)
+		c.sbt.merge(nil)
+		c.mill.merge(nil)
+		c.bazel.merge(nil)
+		c.clojure.merge(nil)
+		c.scripts.merge(nil)
+		c.amper.merge(nil)
+		c.java.merge(nil)
 	} else {
 		c.general.merge(&other.general)
 		c.gradle.merge(&other.gradle)
 		c.maven.merge(&other.maven)
+		c.ant.merge(&other.ant)
 		c.jbang.merge(&other.jbang)
 		c.bach.merge(&other.bach)
+		c.sbt.merge(&other.sbt)
+		c.mill.merge(&other.mill)
+		c.bazel.merge(&other.bazel)
+		c.clojure.merge(&other.clojure)
+		c.scripts.merge(&other.scripts)
+		c.amper.merge(&other.amper)
+		c.java.merge(&other.java)
 	}
 }
 
@@ -194,9 +454,30 @@ func (g *general) merge(other *general) {
 		g.debug = other.d.WithMaybeAsFalse()
 	}
 
-	if len(g.discovery) != 5 && other != nil {
+	if len(g.discovery) == 0 && other != nil {
 		g.discovery = other.discovery
 	}
+
+	if len(g.tool) == 0 && other != nil {
+		g.tool = other.tool
+	}
+
+	if len(g.scanExclude) == 0 && other != nil {
+		g.scanExclude = other.scanExclude
+	}
+
+	if len(g.searchCeiling) == 0 && other != nil {
+		g.searchCeiling = other.searchCeiling
+	}
+
+	if len(g.trust) == 0 && other != nil {
+		g.trust = other.trust
+	}
+	switch g.trust {
+	case "always", "never":
+	default:
+		g.trust = "prompt"
+	}
 }
 
 func (g *gradle) merge(other *gradle) {
@@ -212,6 +493,34 @@ func (g *gradle) merge(other *gradle) {
 		g.defaults = other.d.WithMaybeAsTrue()
 	}
 
+	if g.s != tribool.Maybe || other == nil {
+		g.scope = g.s.WithMaybeAsTrue()
+	} else {
+		g.scope = other.s.WithMaybeAsTrue()
+	}
+
+	if len(g.executable) == 0 && other != nil {
+		g.executable = other.executable
+	}
+
+	if len(g.version) == 0 && other != nil {
+		g.version = other.version
+	}
+
+	if len(g.mismatch) == 0 && other != nil {
+		g.mismatch = other.mismatch
+	}
+	g.mismatch = resolveToolMismatch(g.mismatch)
+
+	if len(g.verifyWrapper) == 0 && other != nil {
+		g.verifyWrapper = other.verifyWrapper
+	}
+	g.verifyWrapper = resolveVerifyWrapper(g.verifyWrapper)
+
+	if len(g.wrapperChecksums) == 0 && other != nil {
+		g.wrapperChecksums = other.wrapperChecksums
+	}
+
 	mp := make(map[string]string)
 	if g.defaults {
 		mp = map[string]string{
@@ -233,6 +542,26 @@ func (g *gradle) merge(other *gradle) {
 	g.mappings = mp
 }
 
+// Resolves a wrapper verification mode, defaults to "warn"
+func resolveVerifyWrapper(mode string) string {
+	switch mode {
+	case "strict", "off":
+		return mode
+	default:
+		return "warn"
+	}
+}
+
+// Resolves a build tool version mismatch mode, defaults to "warn"
+func resolveToolMismatch(mode string) string {
+	switch mode {
+	case "fail", "off":
+		return mode
+	default:
+		return "warn"
+	}
+}
+
 func (m *maven) merge(other *maven) {
 	if m.r != tribool.Maybe || other == nil {
 		m.replace = m.r.WithMaybeAsTrue()
@@ -252,6 +581,41 @@ func (m *maven) merge(other *maven) {
 		m.mvnd = other.m.WithMaybeAsTrue()
 	}
 
+	if len(m.scope) == 0 && other != nil {
+		m.scope = other.scope
+	}
+	if len(m.scope) == 0 {
+		m.scope = "project"
+	}
+
+	if len(m.executable) == 0 && other != nil {
+		m.executable = other.executable
+	}
+
+	if len(m.version) == 0 && other != nil {
+		m.version = other.version
+	}
+
+	if len(m.mismatch) == 0 && other != nil {
+		m.mismatch = other.mismatch
+	}
+	m.mismatch = resolveToolMismatch(m.mismatch)
+
+	if len(m.verifyWrapper) == 0 && other != nil {
+		m.verifyWrapper = other.verifyWrapper
+	}
+	m.verifyWrapper = resolveVerifyWrapper(m.verifyWrapper)
+
+	if len(m.wrapperHosts) == 0 && other != nil && len(other.wrapperHosts) > 0 {
+		m.wrapperHosts = make([]string, len(other.wrapperHosts))
+		copy(m.wrapperHosts, other.wrapperHosts)
+	}
+
+	if len(m.mvndExclude) == 0 && other != nil && len(other.mvndExclude) > 0 {
+		m.mvndExclude = make([]string, len(other.mvndExclude))
+		copy(m.mvndExclude, other.mvndExclude)
+	}
+
 	mp := make(map[string]string)
 	if m.defaults {
 		mp = map[string]string{
@@ -277,20 +641,272 @@ func (m *maven) merge(other *maven) {
 }
 
 func (j *jbang) merge(other *jbang) {
-	if len(j.discovery) == 0 && other != nil && len(other.discovery) == 3 {
-		j.discovery = make([]string, 3)
+	if len(j.discovery) == 0 && other != nil && len(other.discovery) > 0 {
+		j.discovery = make([]string, len(other.discovery))
 		copy(j.discovery, other.discovery)
 	}
+
+	if j.f != tribool.Maybe || other == nil {
+		j.fallback = j.f.WithMaybeAsTrue()
+	} else {
+		j.fallback = other.f.WithMaybeAsTrue()
+	}
+
+	if len(j.executable) == 0 && other != nil {
+		j.executable = other.executable
+	}
+}
+
+func (a *ant) merge(other *ant) {
+	if len(a.executable) == 0 && other != nil {
+		a.executable = other.executable
+	}
+}
+
+func (j *java) merge(other *java) {
+	if len(j.version) == 0 && other != nil {
+		j.version = other.version
+	}
+	if len(j.directories) == 0 && other != nil && len(other.directories) > 0 {
+		j.directories = make([]string, len(other.directories))
+		copy(j.directories, other.directories)
+	}
+	if len(j.mismatch) == 0 && other != nil {
+		j.mismatch = other.mismatch
+	}
+	if len(j.mismatch) == 0 {
+		j.mismatch = "switch"
+	}
+}
+
+func (s *scripts) merge(other *scripts) {
+	if len(s.discovery) == 0 && other != nil && len(other.discovery) > 0 {
+		s.discovery = make([]string, len(other.discovery))
+		copy(s.discovery, other.discovery)
+	}
 }
 
 func (b *bach) merge(other *bach) {
-	if len(b.version) == 0 && other != nil && len(other.version) > 0 {
+	if len(b.version) == 0 && other != nil {
 		b.version = other.version
-	} else {
+	}
+	if len(b.version) == 0 {
 		b.version = "16.0.2"
 	}
 }
 
+func (s *sbt) merge(other *sbt) {
+	if s.r != tribool.Maybe || other == nil {
+		s.replace = s.r.WithMaybeAsTrue()
+	} else {
+		s.replace = other.r.WithMaybeAsTrue()
+	}
+
+	if s.d != tribool.Maybe || other == nil {
+		s.defaults = s.d.WithMaybeAsTrue()
+	} else {
+		s.defaults = other.d.WithMaybeAsTrue()
+	}
+
+	mp := make(map[string]string)
+	if s.defaults {
+		mp = map[string]string{
+			"build":               "compile",
+			"classes":             "compile",
+			"verify":              "test",
+			"check":               "test",
+			"assemble":            "package",
+			"jar":                 "package",
+			"install":             "publishLocal",
+			"publishToMavenLocal": "publishM2",
+			"exec:java":           "run",
+			"dependencies":        "dependencyTree",
+			"dependency:tree":     "dependencyTree"}
+	}
+	if other != nil {
+		for k, v := range other.mappings {
+			mp[k] = v
+		}
+	}
+	for k, v := range s.mappings {
+		mp[k] = v
+	}
+	s.mappings = mp
+}
+
+func (m *mill) merge(other *mill) {
+	if m.r != tribool.Maybe || other == nil {
+		m.replace = m.r.WithMaybeAsTrue()
+	} else {
+		m.replace = other.r.WithMaybeAsTrue()
+	}
+
+	if m.d != tribool.Maybe || other == nil {
+		m.defaults = m.d.WithMaybeAsTrue()
+	} else {
+		m.defaults = other.d.WithMaybeAsTrue()
+	}
+
+	mp := make(map[string]string)
+	if m.defaults {
+		mp = map[string]string{
+			"compile":             "__.compile",
+			"classes":             "__.compile",
+			"test":                "__.test",
+			"check":               "__.test",
+			"verify":              "__.test",
+			"package":             "__.jar",
+			"assemble":            "__.jar",
+			"jar":                 "__.jar",
+			"install":             "__.publishLocal",
+			"publishToMavenLocal": "__.publishM2Local",
+			"exec:java":           "run",
+			"dependencies":        "__.ivyDepsTree",
+			"dependency:tree":     "__.ivyDepsTree"}
+	}
+	if other != nil {
+		for k, v := range other.mappings {
+			mp[k] = v
+		}
+	}
+	for k, v := range m.mappings {
+		mp[k] = v
+	}
+	m.mappings = mp
+}
+
+func (b *bazel) merge(other *bazel) {
+	if b.r != tribool.Maybe || other == nil {
+		b.replace = b.r.WithMaybeAsTrue()
+	} else {
+		b.replace = other.r.WithMaybeAsTrue()
+	}
+
+	if b.d != tribool.Maybe || other == nil {
+		b.defaults = b.d.WithMaybeAsTrue()
+	} else {
+		b.defaults = other.d.WithMaybeAsTrue()
+	}
+
+	mp := make(map[string]string)
+	if b.defaults {
+		mp = map[string]string{
+			"compile":   "build",
+			"classes":   "build",
+			"package":   "build",
+			"assemble":  "build",
+			"jar":       "build",
+			"verify":    "test",
+			"check":     "test",
+			"exec:java": "run"}
+	}
+	if other != nil {
+		for k, v := range other.mappings {
+			mp[k] = v
+		}
+	}
+	for k, v := range b.mappings {
+		mp[k] = v
+	}
+	b.mappings = mp
+}
+
+func (c *clojure) merge(other *clojure) {
+	if c.r != tribool.Maybe || other == nil {
+		c.replace = c.r.WithMaybeAsTrue()
+	} else {
+		c.replace = other.r.WithMaybeAsTrue()
+	}
+
+	if c.d != tribool.Maybe || other == nil {
+		c.defaults = c.d.WithMaybeAsTrue()
+	} else {
+		c.defaults = other.d.WithMaybeAsTrue()
+	}
+
+	lein := make(map[string]string)
+	deps := make(map[string]string)
+	if c.defaults {
+		lein = map[string]string{
+			"build":               "uberjar",
+			"classes":             "compile",
+			"verify":              "test",
+			"check":               "test",
+			"package":             "jar",
+			"assemble":            "jar",
+			"publishToMavenLocal": "install",
+			"exec:java":           "run",
+			"dependencies":        "deps :tree",
+			"dependency:tree":     "deps :tree"}
+		deps = map[string]string{
+			"build":               "-T:build uber",
+			"test":                "-X:test",
+			"verify":              "-X:test",
+			"check":               "-X:test",
+			"package":             "-T:build jar",
+			"assemble":            "-T:build jar",
+			"jar":                 "-T:build jar",
+			"install":             "-T:build install",
+			"publishToMavenLocal": "-T:build install",
+			"clean":               "-T:build clean",
+			"dependencies":        "-X:deps tree",
+			"dependency:tree":     "-X:deps tree"}
+	}
+	if other != nil {
+		for k, v := range other.lein {
+			lein[k] = v
+		}
+		for k, v := range other.deps {
+			deps[k] = v
+		}
+	}
+	for k, v := range c.lein {
+		lein[k] = v
+	}
+	for k, v := range c.deps {
+		deps[k] = v
+	}
+	c.lein = lein
+	c.deps = deps
+}
+
+func (a *amper) merge(other *amper) {
+	if a.r != tribool.Maybe || other == nil {
+		a.replace = a.r.WithMaybeAsTrue()
+	} else {
+		a.replace = other.r.WithMaybeAsTrue()
+	}
+
+	if a.d != tribool.Maybe || other == nil {
+		a.defaults = a.d.WithMaybeAsTrue()
+	} else {
+		a.defaults = other.d.WithMaybeAsTrue()
+	}
+
+	mp := make(map[string]string)
+	if a.defaults {
+		mp = map[string]string{
+			"compile":             "build",
+			"classes":             "build",
+			"verify":              "test",
+			"check":               "test",
+			"assemble":            "package",
+			"jar":                 "package",
+			"install":             "publish mavenLocal",
+			"publishToMavenLocal": "publish mavenLocal",
+			"exec:java":           "run"}
+	}
+	if other != nil {
+		for k, v := range other.mappings {
+			mp[k] = v
+		}
+	}
+	for k, v := range a.mappings {
+		mp[k] = v
+	}
+	a.mappings = mp
+}
+
 // ReadUserConfig reads user config
 func ReadUserConfig(context Context) *Config {
 	homedir := context.GetHomeDir()
@@ -305,7 +921,34 @@ func ReadUserConfig(context Context) *Config {
 // ReadConfig reads and merges project & user config
 func ReadConfig(context Context, rootdir string) *Config {
 	uconfig := ReadUserConfig(context)
-	pconfig := ReadConfigFile(context, filepath.Join(rootdir, ".gm.toml"))
+	pfile, _ := filepath.Abs(filepath.Join(rootdir, ".gm.toml"))
+	pconfig := ReadConfigFile(context, pfile)
+	if context.FileExists(pfile) && pfile != filepath.Join(context.GetHomeDir(), ".gm.toml") {
+		pconfig.projectFile = pfile
+	}
+
+	// trust and wrapper verification guard against the project itself thus
+	// they may only be configured by the user
+	pconfig.general.trust = ""
+	pconfig.gradle.verifyWrapper = ""
+	pconfig.gradle.wrapperChecksums = ""
+	pconfig.maven.verifyWrapper = ""
+	pconfig.maven.wrapperHosts = nil
+
+	// executables and java settings are withheld until the project config is trusted
+	if len(pconfig.projectFile) > 0 && !isTrustedFile(context, uconfig, pconfig.projectFile) {
+		pconfig.withheld = &withheldSettings{
+			gradleExecutable: pconfig.gradle.executable,
+			mavenExecutable:  pconfig.maven.executable,
+			antExecutable:    pconfig.ant.executable,
+			jbangExecutable:  pconfig.jbang.executable,
+			java:             pconfig.java}
+		pconfig.gradle.executable = ""
+		pconfig.maven.executable = ""
+		pconfig.ant.executable = ""
+		pconfig.jbang.executable = ""
+		pconfig.java = java{}
+	}
 
 	pconfig.merge(uconfig)
 
@@ -324,7 +967,7 @@ func ReadConfigFile(context Context, path string) *Config {
 	if err == nil {
 		toml.Unmarshal(doc, &config)
 	} else {
-		fmt.Println(err)
+		fmt.Fprintln(context.GetStdout(), err)
 	}
 
 	t, err := toml.LoadBytes(doc)
@@ -336,12 +979,56 @@ func ReadConfigFile(context Context, path string) *Config {
 	resolveSectionGeneral(t, config)
 	resolveSectionGradle(t, config)
 	resolveSectionMaven(t, config)
+	resolveSectionAnt(t, config)
 	resolveSectionJbang(t, config)
+	resolveSectionScripts(t, config)
 	resolveSectionBach(t, config)
+	resolveSectionSbt(t, config)
+	resolveSectionMill(t, config)
+	resolveSectionBazel(t, config)
+	resolveSectionClojure(t, config)
+	resolveSectionAmper(t, config)
+	resolveSectionJava(t, config)
+
+	if err := config.validate(); err != nil {
+		fmt.Fprintln(context.GetStdout(), path+": "+err.Error())
+		context.Exit(-1)
+	}
 
 	return config
 }
 
+// Checks that settings taking one of a fixed set of values hold a known value
+func (c *Config) validate() error {
+	settings := []struct {
+		key    string
+		value  string
+		values []string
+	}{
+		{"[general] trust", c.general.trust, []string{"prompt", "always", "never"}},
+		{"[gradle] mismatch", c.gradle.mismatch, []string{"warn", "fail", "off"}},
+		{"[gradle] verifyWrapper", c.gradle.verifyWrapper, []string{"warn", "strict", "off"}},
+		{"[maven] scope", c.maven.scope, []string{"project", "module"}},
+		{"[maven] mismatch", c.maven.mismatch, []string{"warn", "fail", "off"}},
+		{"[maven] verifyWrapper", c.maven.verifyWrapper, []string{"warn", "strict", "off"}},
+		{"[java] mismatch", c.java.mismatch, []string{"warn", "switch", "fail", "off"}}}
+
+	for _, setting := range settings {
+		if len(setting.value) == 0 {
+			continue
+		}
+		known := false
+		for _, value := range setting.values {
+			known = known || setting.value == value
+		}
+		if !known {
+			return fmt.Errorf("Invalid value '%s' for %s, expected one of %s", setting.value, setting.key, strings.Join(setting.values, ", "))
+		}
+	}
+
+	return nil
+}
+
 func resolveSectionTheme(t *toml.Tree, config *Config) {
 	tt := t.Get("theme")
 	if tt != nil {
@@ -424,6 +1111,14 @@ func resolveSectionGeneral(t *toml.Tree, config *Config) {
 		if v != nil {
 			config.general.d = tribool.FromBool(v.(bool))
 		}
+		v = table.Get("trust")
+		if v != nil {
+			config.general.trust = v.(string)
+		}
+		v = table.Get("tool")
+		if v != nil {
+			config.general.tool = v.(string)
+		}
 		v = table.Get("discovery")
 		if v != nil {
 			data := v.([]interface{})
@@ -432,6 +1127,22 @@ func resolveSectionGeneral(t *toml.Tree, config *Config) {
 				config.general.discovery[i] = e.(string)
 			}
 		}
+		v = table.Get("scanExclude")
+		if v != nil {
+			data := v.([]interface{})
+			config.general.scanExclude = make([]string, len(data))
+			for i, e := range data {
+				config.general.scanExclude[i] = e.(string)
+			}
+		}
+		v = table.Get("searchCeiling")
+		if v != nil {
+			data := v.([]interface{})
+			config.general.searchCeiling = make([]string, len(data))
+			for i, e := range data {
+				config.general.searchCeiling[i] = e.(string)
+			}
+		}
 	}
 }
 
@@ -447,6 +1158,30 @@ func resolveSectionGradle(t *toml.Tree, config *Config) {
 		if v != nil {
 			config.gradle.d = tribool.FromBool(v.(bool))
 		}
+		v = table.Get("scope")
+		if v != nil {
+			config.gradle.s = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("executable")
+		if v != nil {
+			config.gradle.executable = v.(string)
+		}
+		v = table.Get("version")
+		if v != nil {
+			config.gradle.version = v.(string)
+		}
+		v = table.Get("mismatch")
+		if v != nil {
+			config.gradle.mismatch = v.(string)
+		}
+		v = table.Get("verifyWrapper")
+		if v != nil {
+			config.gradle.verifyWrapper = v.(string)
+		}
+		v = table.Get("wrapperChecksums")
+		if v != nil {
+			config.gradle.wrapperChecksums = v.(string)
+		}
 		v = table.Get("mappings")
 		if v != nil {
 			m := v.(*toml.Tree)
@@ -473,6 +1208,42 @@ func resolveSectionMaven(t *toml.Tree, config *Config) {
 		if v != nil {
 			config.maven.m = tribool.FromBool(v.(bool))
 		}
+		v = table.Get("scope")
+		if v != nil {
+			config.maven.scope = v.(string)
+		}
+		v = table.Get("executable")
+		if v != nil {
+			config.maven.executable = v.(string)
+		}
+		v = table.Get("version")
+		if v != nil {
+			config.maven.version = v.(string)
+		}
+		v = table.Get("mismatch")
+		if v != nil {
+			config.maven.mismatch = v.(string)
+		}
+		v = table.Get("verifyWrapper")
+		if v != nil {
+			config.maven.verifyWrapper = v.(string)
+		}
+		v = table.Get("wrapperHosts")
+		if v != nil {
+			data := v.([]interface{})
+			config.maven.wrapperHosts = make([]string, len(data))
+			for i, e := range data {
+				config.maven.wrapperHosts[i] = e.(string)
+			}
+		}
+		v = table.Get("mvndExclude")
+		if v != nil {
+			data := v.([]interface{})
+			config.maven.mvndExclude = make([]string, len(data))
+			for i, e := range data {
+				config.maven.mvndExclude[i] = e.(string)
+			}
+		}
 		v = table.Get("mappings")
 		if v != nil {
 			m := v.(*toml.Tree)
@@ -484,6 +1255,17 @@ func resolveSectionMaven(t *toml.Tree, config *Config) {
 	}
 }
 
+func resolveSectionAnt(t *toml.Tree, config *Config) {
+	tt := t.Get("ant")
+	if tt != nil {
+		table := tt.(*toml.Tree)
+		v := table.Get("executable")
+		if v != nil {
+			config.ant.executable = v.(string)
+		}
+	}
+}
+
 func resolveSectionJbang(t *toml.Tree, config *Config) {
 	tt := t.Get("jbang")
 	if tt != nil {
@@ -496,6 +1278,29 @@ func resolveSectionJbang(t *toml.Tree, config *Config) {
 				config.jbang.discovery[i] = e.(string)
 			}
 		}
+		v = table.Get("fallback")
+		if v != nil {
+			config.jbang.f = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("executable")
+		if v != nil {
+			config.jbang.executable = v.(string)
+		}
+	}
+}
+
+func resolveSectionScripts(t *toml.Tree, config *Config) {
+	tt := t.Get("scripts")
+	if tt != nil {
+		table := tt.(*toml.Tree)
+		v := table.Get("discovery")
+		if v != nil {
+			data := v.([]interface{})
+			config.scripts.discovery = make([]string, len(data))
+			for i, e := range data {
+				config.scripts.discovery[i] = e.(string)
+			}
+		}
 	}
 }
 
@@ -509,3 +1314,149 @@ func resolveSectionBach(t *toml.Tree, config *Config) {
 		}
 	}
 }
+
+func resolveSectionSbt(t *toml.Tree, config *Config) {
+	tt := t.Get("sbt")
+	if tt != nil {
+		table := tt.(*toml.Tree)
+		v := table.Get("replace")
+		if v != nil {
+			config.sbt.r = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("defaults")
+		if v != nil {
+			config.sbt.d = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("mappings")
+		if v != nil {
+			m := v.(*toml.Tree)
+			for i := range m.Keys() {
+				key := m.Keys()[i]
+				config.sbt.mappings[key] = m.Get(key).(string)
+			}
+		}
+	}
+}
+
+func resolveSectionMill(t *toml.Tree, config *Config) {
+	tt := t.Get("mill")
+	if tt != nil {
+		table := tt.(*toml.Tree)
+		v := table.Get("replace")
+		if v != nil {
+			config.mill.r = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("defaults")
+		if v != nil {
+			config.mill.d = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("mappings")
+		if v != nil {
+			m := v.(*toml.Tree)
+			for i := range m.Keys() {
+				key := m.Keys()[i]
+				config.mill.mappings[key] = m.Get(key).(string)
+			}
+		}
+	}
+}
+
+func resolveSectionBazel(t *toml.Tree, config *Config) {
+	tt := t.Get("bazel")
+	if tt != nil {
+		table := tt.(*toml.Tree)
+		v := table.Get("replace")
+		if v != nil {
+			config.bazel.r = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("defaults")
+		if v != nil {
+			config.bazel.d = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("mappings")
+		if v != nil {
+			m := v.(*toml.Tree)
+			for i := range m.Keys() {
+				key := m.Keys()[i]
+				config.bazel.mappings[key] = m.Get(key).(string)
+			}
+		}
+	}
+}
+
+func resolveSectionClojure(t *toml.Tree, config *Config) {
+	tt := t.Get("clojure")
+	if tt != nil {
+		table := tt.(*toml.Tree)
+		v := table.Get("replace")
+		if v != nil {
+			config.clojure.r = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("defaults")
+		if v != nil {
+			config.clojure.d = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("lein.mappings")
+		if v != nil {
+			m := v.(*toml.Tree)
+			for i := range m.Keys() {
+				key := m.Keys()[i]
+				config.clojure.lein[key] = m.Get(key).(string)
+			}
+		}
+		v = table.Get("deps.mappings")
+		if v != nil {
+			m := v.(*toml.Tree)
+			for i := range m.Keys() {
+				key := m.Keys()[i]
+				config.clojure.deps[key] = m.Get(key).(string)
+			}
+		}
+	}
+}
+
+func resolveSectionAmper(t *toml.Tree, config *Config) {
+	tt := t.Get("amper")
+	if tt != nil {
+		table := tt.(*toml.Tree)
+		v := table.Get("replace")
+		if v != nil {
+			config.amper.r = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("defaults")
+		if v != nil {
+			config.amper.d = tribool.FromBool(v.(bool))
+		}
+		v = table.Get("mappings")
+		if v != nil {
+			m := v.(*toml.Tree)
+			for i := range m.Keys() {
+				key := m.Keys()[i]
+				config.amper.mappings[key] = m.Get(key).(string)
+			}
+		}
+	}
+}
+
+func resolveSectionJava(t *toml.Tree, config *Config) {
+	tt := t.Get("java")
+	if tt != nil {
+		table := tt.(*toml.Tree)
+		v := table.Get("version")
+		if v != nil {
+			config.java.version = v.(string)
+		}
+		v = table.Get("directories")
+		if v != nil {
+			data := v.([]interface{})
+			config.java.directories = make([]string, len(data))
+			for i, e := range data {
+				config.java.directories[i] = e.(string)
+			}
+		}
+		v = table.Get("mismatch")
+		if v != nil {
+			config.java.mismatch = v.(string)
+		}
+	}
+}
@@ -71,3 +71,30 @@ func TestLoadConfig(t *testing.T) {
 		t.Errorf("maven.mappings.compile: got %s, want %s", config.gradle.mappings["compileJava"], "compile")
 	}
 }
+
+func TestValidateConfig(t *testing.T) {
+	var checks = []struct {
+		title    string
+		modify   func(c *Config)
+		expected bool
+	}{
+		{"defaults", func(c *Config) {}, true},
+		{"gradle.verifyWrapper", func(c *Config) { c.gradle.verifyWrapper = "strict" }, true},
+		{"gradle.verifyWrapper typo", func(c *Config) { c.gradle.verifyWrapper = "stict" }, false},
+		{"maven.verifyWrapper typo", func(c *Config) { c.maven.verifyWrapper = "warning" }, false},
+		{"maven.mismatch typo", func(c *Config) { c.maven.mismatch = "failed" }, false},
+		{"maven.scope typo", func(c *Config) { c.maven.scope = "modules" }, false},
+		{"general.trust typo", func(c *Config) { c.general.trust = "yes" }, false},
+		{"java.mismatch", func(c *Config) { c.java.mismatch = "fail" }, true},
+	}
+
+	for _, check := range checks {
+		t.Run(check.title, func(t *testing.T) {
+			config := newConfig()
+			check.modify(config)
+			if err := config.validate(); (err == nil) != check.expected {
+				t.Errorf("got %v, want valid = %t", err, check.expected)
+			}
+		})
+	}
+}
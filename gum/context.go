@@ -18,6 +18,7 @@ package gum
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 	"strings"
@@ -88,12 +89,27 @@ func (c DefaultContext) GetHomeDir() string {
 	return os.Getenv("HOME")
 }
 
+// GetEnv gets the value of the given environment variable
+func (c DefaultContext) GetEnv(key string) string {
+	return os.Getenv(key)
+}
+
 // FileExists checks if a file exists
 func (c DefaultContext) FileExists(name string) bool {
 	_, err := os.Stat(name)
 	return !os.IsNotExist(err)
 }
 
+// GetStdout returns the writer receiving the standard output of executed tools
+func (c DefaultContext) GetStdout() io.Writer {
+	return os.Stdout
+}
+
+// GetStderr returns the writer receiving the standard error of executed tools
+func (c DefaultContext) GetStderr() io.Writer {
+	return os.Stderr
+}
+
 // Exit causes the current program to exit with the given status code.
 func (c DefaultContext) Exit(code int) {
 	os.Exit(code)
@@ -108,7 +124,10 @@ type testContext struct {
 	workingDir string
 	homeDir    string
 	paths      []string
+	env        map[string]string
 	exitCode   int
+	// receives the output when set, defaults to os.Stdout and os.Stderr
+	stdout io.Writer
 }
 
 func (c testContext) IsQuiet() bool {
@@ -139,11 +158,29 @@ func (c testContext) GetPaths() []string {
 	return c.paths
 }
 
+func (c testContext) GetEnv(key string) string {
+	return c.env[key]
+}
+
 func (c testContext) FileExists(name string) bool {
 	_, err := os.Stat(name)
 	return !os.IsNotExist(err)
 }
 
+func (c testContext) GetStdout() io.Writer {
+	if c.stdout != nil {
+		return c.stdout
+	}
+	return os.Stdout
+}
+
+func (c testContext) GetStderr() io.Writer {
+	if c.stdout != nil {
+		return c.stdout
+	}
+	return os.Stderr
+}
+
 func (c testContext) Exit(code int) {
 	c.exitCode = code
 }
@@ -0,0 +1,448 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tools able to run in fan-out mode
+var fanOutTools = []string{"gradle", "maven", "ant"}
+
+// fanOutOptions are read from the tool flags, the remaining ones are passed to every build
+type fanOutOptions struct {
+	jobs     int
+	includes []string
+	excludes []string
+	affected string
+	dryRun   bool
+	args     []string
+}
+
+// fanOutRun is the build of a single project
+type fanOutRun struct {
+	name     string
+	tool     string
+	context  *fanOutContext
+	config   *Config
+	wrappers []string
+	// affected Maven modules or Gradle projects, empty when the whole build runs
+	selection []string
+	execute   func() int
+	exitCode  int
+	duration  time.Duration
+}
+
+// fanOutContext runs a build at the given directory and prefixes its output
+type fanOutContext struct {
+	Context
+	workingDir string
+	stdout     *prefixWriter
+	stderr     *prefixWriter
+}
+
+func (c fanOutContext) IsExplicit() bool {
+	return false
+}
+
+func (c fanOutContext) GetWorkingDir() string {
+	return c.workingDir
+}
+
+func (c fanOutContext) CheckIsExecutable(file string) {
+	if !c.IsWindows() {
+		fileInfo, err := os.Stat(file)
+		if err != nil || fileInfo.Mode().Perm()&0111 == 0 {
+			fmt.Fprintf(c.stderr, "%s is not executable", file)
+			fmt.Fprintln(c.stderr)
+			c.Exit(-1)
+		}
+	}
+}
+
+func (c fanOutContext) GetStdout() io.Writer {
+	return c.stdout
+}
+
+func (c fanOutContext) GetStderr() io.Writer {
+	return c.stderr
+}
+
+func (c fanOutContext) Exit(code int) {
+	panic(projectExit{code: code})
+}
+
+// prefixWriter prefixes every line with the project name. Lines are written whole
+// thus the output of concurrent builds does not interleave within a line.
+// Output is held until the writer is started, as the prefix is not known beforehand.
+type prefixWriter struct {
+	prefix string
+	out    io.Writer
+	lock   *sync.Mutex
+	buf    []byte
+	held   bool
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for !w.held {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.writeLine(w.buf[:i+1])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Start sets the prefix and writes the lines held so far
+func (w *prefixWriter) Start(prefix string) {
+	w.prefix = prefix
+	w.held = false
+	w.Write(nil)
+}
+
+// Flush writes the last line, if it was not terminated
+func (w *prefixWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.writeLine(append(w.buf, '\n'))
+		w.buf = nil
+	}
+}
+
+func (w *prefixWriter) writeLine(line []byte) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	io.WriteString(w.out, w.prefix)
+	w.out.Write(line)
+}
+
+// FanOut runs the same build at every Gradle, Maven, and Ant project found below the working directory.
+// Returns a non zero exit code if any of the builds failed.
+func FanOut(context Context, args *ParsedArgs) int {
+	pwd := context.GetWorkingDir()
+	config := ReadConfig(context, pwd)
+	options := parseFanOutArgs(args.Tool)
+
+	// projects are resolved once, at the context their builds run with
+	lock := &sync.Mutex{}
+	contextFor := func(dir string) Context {
+		return &fanOutContext{
+			Context:    context,
+			workingDir: dir,
+			stdout:     &prefixWriter{out: context.GetStdout(), lock: lock, held: true},
+			stderr:     &prefixWriter{out: context.GetStderr(), lock: lock, held: true}}
+	}
+
+	projects := selectFanOutProjects(scanProjects(context, config, resolveFanOutArgs(args, options), contextFor), options)
+	if len(projects) == 0 {
+		fmt.Fprintln(context.GetStdout(), "Did not find a Gradle, Maven, or Ant project")
+		return -1
+	}
+
+	var files []string
+	if len(options.affected) > 0 {
+		changes, err := findChangedFiles(pwd, options.affected)
+		if err != nil {
+			fmt.Fprintln(context.GetStdout(), "Could not find changes since "+options.affected+": "+err.Error())
+			return -1
+		}
+		files = changes
+		projects = filterAffectedProjects(pwd, projects, files)
+		if len(projects) == 0 {
+			fmt.Fprintln(context.GetStdout(), "No projects affected by changes since "+options.affected)
+			return 0
+		}
+	}
+
+	width := 0
+	for _, p := range projects {
+		if len(resolveFanOutName(pwd, p)) > width {
+			width = len(resolveFanOutName(pwd, p))
+		}
+	}
+
+	runs := make([]*fanOutRun, 0)
+	for _, p := range projects {
+		name := resolveFanOutName(pwd, p)
+		prefix := fmt.Sprintf("%-*s | ", width, name)
+		run := &fanOutRun{name: name, tool: p.Tool, exitCode: -1}
+		run.context = p.context.(*fanOutContext)
+		run.context.stdout.Start(prefix)
+		run.context.stderr.Start(prefix)
+		resolveFanOutRun(run, p.candidate, files)
+		runs = append(runs, run)
+	}
+
+	if options.dryRun {
+		printFanOutPlan(context.GetStdout(), config, runs)
+		return 0
+	}
+
+	// trust is checked upfront as it may prompt
+	for _, run := range runs {
+		if run.execute != nil && !trustProject(run.context, run.config, run.wrappers...) {
+			run.execute = nil
+		}
+	}
+
+	sem := make(chan struct{}, options.jobs)
+	var wg sync.WaitGroup
+	for _, run := range runs {
+		if run.execute == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(run *fanOutRun) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			executeFanOutRun(run)
+		}(run)
+	}
+	wg.Wait()
+
+	return printFanOutSummary(context.GetStdout(), config, runs)
+}
+
+// Every build gets the remaining tool flags, the arguments, and the gum flags but -gf
+func resolveFanOutArgs(args *ParsedArgs, options fanOutOptions) *ParsedArgs {
+	gum := map[string]struct{}{"gq": {}}
+	for flag := range args.Gum {
+		if flag != "gf" {
+			gum[flag] = struct{}{}
+		}
+	}
+	return &ParsedArgs{
+		Gum:  gum,
+		Tool: appendSafe(make([]string, 0), options.args),
+		Args: appendSafe(make([]string, 0), args.Args)}
+}
+
+// Parses -j N, --include glob, --exclude glob, --affected base, and --dry-run.
+// Flags taking a value may also be given as -jN or --include=glob.
+func parseFanOutArgs(args []string) fanOutOptions {
+	options := fanOutOptions{
+		jobs:     runtime.NumCPU(),
+		includes: make([]string, 0),
+		excludes: make([]string, 0),
+		args:     make([]string, 0)}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		value := ""
+		name := arg
+		if strings.HasPrefix(arg, "--") && strings.Contains(arg, "=") {
+			name = arg[:strings.Index(arg, "=")]
+			value = arg[strings.Index(arg, "=")+1:]
+		} else if strings.HasPrefix(arg, "-j") && len(arg) > 2 {
+			name = "-j"
+			value = arg[2:]
+		}
+
+		switch name {
+		case "--dry-run":
+			options.dryRun = true
+			continue
+		case "-j", "--jobs", "--include", "--exclude", "--affected":
+			if len(value) == 0 && i+1 < len(args) {
+				i = i + 1
+				value = args[i]
+			}
+		default:
+			options.args = append(options.args, arg)
+			continue
+		}
+
+		switch name {
+		case "-j", "--jobs":
+			jobs, err := strconv.Atoi(value)
+			if err == nil && jobs > 0 {
+				options.jobs = jobs
+			}
+		case "--include":
+			options.includes = append(options.includes, value)
+		case "--exclude":
+			options.excludes = append(options.excludes, value)
+		case "--affected":
+			options.affected = value
+		}
+	}
+
+	return options
+}
+
+// Keeps a single tool per directory, the one pinned with [general] tool or the first one found.
+// Projects are matched by path relative to the working directory or by name.
+func selectFanOutProjects(projects []ScannedProject, options fanOutOptions) []ScannedProject {
+	selected := make([]ScannedProject, 0)
+	for _, p := range projects {
+		if !isFanOutTool(p.Tool) || !matchesFanOutGlobs(p.Dir, options) {
+			continue
+		}
+
+		found := -1
+		for i, s := range selected {
+			if s.Dir == p.Dir {
+				found = i
+			}
+		}
+		if found < 0 {
+			selected = append(selected, p)
+		} else if p.candidate != nil && p.candidate.config.general.tool == p.Tool {
+			selected[found] = p
+		}
+	}
+	return selected
+}
+
+func isFanOutTool(tool string) bool {
+	for _, t := range fanOutTools {
+		if t == tool {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesFanOutGlobs(dir string, options fanOutOptions) bool {
+	matches := func(globs []string) bool {
+		for _, glob := range globs {
+			glob = strings.TrimSuffix(strings.TrimSpace(glob), "/")
+			if m, _ := path.Match(glob, dir); m {
+				return true
+			}
+			if m, _ := path.Match(glob, path.Base(dir)); m {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(options.includes) > 0 && !matches(options.includes) {
+		return false
+	}
+	return !matches(options.excludes)
+}
+
+func resolveFanOutName(pwd string, project ScannedProject) string {
+	if project.Dir == "." {
+		return filepath.Base(pwd)
+	}
+	return project.Dir
+}
+
+// Takes the command resolved by the scan, when files are given only the Maven modules
+// or Gradle projects holding them are built
+func resolveFanOutRun(run *fanOutRun, candidate *toolCandidate, files []string) {
+	if candidate != nil {
+		switch c := candidate.command.(type) {
+		case *GradleCommand:
+			if len(files) > 0 {
+				c.affectedPaths = resolveAffectedGradleProjects(c.settingsFile, files)
+				run.selection = c.affectedPaths
+			}
+			run.config, run.wrappers, run.execute = c.config, c.wrappers(), c.Execute
+		case *MavenCommand:
+			if len(files) > 0 {
+				c.affectedModules = resolveAffectedMavenModules(c.rootBuildFile, files)
+				run.selection = c.affectedModules
+			}
+			run.config, run.wrappers, run.execute = c.config, c.wrappers(), c.Execute
+		case *AntCommand:
+			run.config, run.execute = c.config, c.Execute
+		}
+	}
+
+	if run.execute == nil {
+		fmt.Fprintf(run.context.stderr, "No %s executable found", run.tool)
+		fmt.Fprintln(run.context.stderr)
+		run.context.stderr.Flush()
+	}
+}
+
+func executeFanOutRun(run *fanOutRun) {
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			exit, ok := r.(projectExit)
+			if !ok {
+				panic(r)
+			}
+			run.exitCode = exit.code
+		}
+		run.duration = time.Since(start)
+		run.context.stdout.Flush()
+		run.context.stderr.Flush()
+	}()
+
+	run.exitCode = run.execute()
+}
+
+// Prints the builds that would run, along with the affected modules or projects
+func printFanOutPlan(out io.Writer, config *Config, runs []*fanOutRun) {
+	rows := make([][]string, 0)
+	for _, run := range runs {
+		selection := "(all)"
+		if len(run.selection) > 0 {
+			selection = strings.Join(run.selection, " ")
+		}
+		if run.execute == nil {
+			selection = "(not found)"
+		}
+		rows = append(rows, []string{run.name, run.tool, selection})
+	}
+	config.theme.t.PrintTable(out, []string{"PROJECT", "TOOL", "SELECTION"}, rows)
+}
+
+// Prints the exit code and duration of every build, returns -1 if any of them failed
+func printFanOutSummary(out io.Writer, config *Config, runs []*fanOutRun) int {
+	failed := 0
+	rows := make([][]string, 0)
+	for _, run := range runs {
+		duration := "-"
+		if run.duration > 0 {
+			duration = run.duration.Round(time.Millisecond).String()
+		}
+		if run.exitCode != 0 {
+			failed = failed + 1
+		}
+		rows = append(rows, []string{run.name, run.tool, strconv.Itoa(run.exitCode), duration})
+	}
+
+	fmt.Fprintln(out)
+	config.theme.t.PrintTable(out, []string{"PROJECT", "TOOL", "EXIT", "DURATION"}, rows)
+
+	if failed > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "%d of %d projects failed", failed, len(runs))
+		fmt.Fprintln(out)
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,217 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFanOutArgs(t *testing.T) {
+	// given:
+	args := []string{"-j", "2", "--include=services/*", "--exclude", "legacy", "--offline"}
+
+	// when:
+	options := parseFanOutArgs(args)
+
+	// then:
+	var checks = []struct {
+		title    string
+		actual   string
+		expected string
+	}{
+		{"Jobs", strings.Repeat("*", options.jobs), "**"},
+		{"Includes", strings.Join(options.includes, " "), "services/*"},
+		{"Excludes", strings.Join(options.excludes, " "), "legacy"},
+		{"Args", strings.Join(options.args, " "), "--offline"},
+	}
+
+	for _, check := range checks {
+		t.Run(check.title, func(t *testing.T) {
+			if check.actual != check.expected {
+				t.Errorf("%s: got %s want %s", check.title, check.actual, check.expected)
+			}
+		})
+	}
+}
+
+func TestFanOutGlobs(t *testing.T) {
+	// given:
+	options := parseFanOutArgs([]string{"--include", "services/*", "--exclude", "legacy"})
+
+	var checks = []struct {
+		title    string
+		dir      string
+		expected bool
+	}{
+		{"Included", "services/orders", true},
+		{"NotIncluded", "tools/release", false},
+		{"ExcludedByName", "services/legacy", false},
+	}
+
+	for _, check := range checks {
+		t.Run(check.title, func(t *testing.T) {
+			if matchesFanOutGlobs(check.dir, options) != check.expected {
+				t.Errorf("%s: got %t want %t", check.title, !check.expected, check.expected)
+			}
+		})
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	// given:
+	out := &bytes.Buffer{}
+	w := &prefixWriter{prefix: "app | ", out: out, lock: &sync.Mutex{}}
+
+	// when:
+	w.Write([]byte("first\nsec"))
+	w.Write([]byte("ond\nthird"))
+	w.Flush()
+
+	// then:
+	expected := "app | first\napp | second\napp | third\n"
+	if out.String() != expected {
+		t.Errorf("output: got %q want %q", out.String(), expected)
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	// given:
+	home := t.TempDir()
+	pwd := t.TempDir()
+	os.WriteFile(filepath.Join(home, ".gm.toml"), []byte("[general]\ntrust = \"always\"\n"), 0644)
+	for _, dir := range []string{"app", "lib", "legacy"} {
+		os.Mkdir(filepath.Join(pwd, dir), 0755)
+	}
+	os.WriteFile(filepath.Join(pwd, "app", "settings.gradle"), []byte(""), 0644)
+	os.WriteFile(filepath.Join(pwd, "app", "gradlew"), []byte("#!/bin/sh\necho \"$@\" > args.txt\nexit 3\n"), 0755)
+	os.WriteFile(filepath.Join(pwd, "lib", "pom.xml"), []byte(""), 0644)
+	os.WriteFile(filepath.Join(pwd, "lib", "mvnw"), []byte("#!/bin/sh\necho \"$@\" > args.txt\n"), 0755)
+	os.WriteFile(filepath.Join(pwd, "legacy", "pom.xml"), []byte(""), 0644)
+	os.WriteFile(filepath.Join(pwd, "legacy", "mvnw"), []byte("#!/bin/sh\necho \"$@\" > args.txt\n"), 0755)
+
+	context := testContext{
+		quiet:      true,
+		workingDir: pwd,
+		homeDir:    home}
+
+	// when:
+	args := ParseArgs([]string{"-gf", "-j", "2", "--exclude", "legacy", "build"})
+	code := FanOut(context, &args)
+
+	// then:
+	if code != -1 {
+		t.Errorf("exit code: got %d want -1", code)
+	}
+	for _, check := range []struct {
+		dir      string
+		expected string
+	}{{"app", "build\n"}, {"lib", "verify\n"}} {
+		data, _ := os.ReadFile(filepath.Join(pwd, check.dir, "args.txt"))
+		if !strings.HasSuffix(string(data), check.expected) {
+			t.Errorf("%s args: got %q want suffix %q", check.dir, string(data), check.expected)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(pwd, "legacy", "args.txt")); err == nil {
+		t.Error("legacy: got executed, want excluded")
+	}
+}
+
+func TestFanOutKeepsOutputAndExitsPerProject(t *testing.T) {
+	// given:
+	home := t.TempDir()
+	pwd := t.TempDir()
+	os.WriteFile(filepath.Join(home, ".gm.toml"), []byte("[general]\ntrust = \"always\"\n"), 0644)
+	for _, dir := range []string{"app", "lib"} {
+		os.Mkdir(filepath.Join(pwd, dir), 0755)
+	}
+	os.WriteFile(filepath.Join(pwd, "app", "settings.gradle"), []byte(""), 0644)
+	os.WriteFile(filepath.Join(pwd, "app", "gradlew"), []byte("#!/bin/sh\necho \"$@\" > args.txt\n"), 0755)
+	os.WriteFile(filepath.Join(pwd, "lib", "pom.xml"), []byte(""), 0644)
+	os.WriteFile(filepath.Join(pwd, "lib", "mvnw"), []byte("#!/bin/sh\necho \"$@\" > args.txt\n"), 0755)
+	os.WriteFile(filepath.Join(pwd, "lib", ".gm.toml"), []byte("[maven]\nmismatch = \"bogus\"\n"), 0644)
+
+	out := &bytes.Buffer{}
+	context := testContext{
+		quiet:      true,
+		workingDir: pwd,
+		homeDir:    home,
+		stdout:     out}
+
+	// when:
+	args := ParseArgs([]string{"-gf", "-gc", "build"})
+	code := FanOut(context, &args)
+
+	// then:
+	if code != -1 {
+		t.Errorf("exit code: got %d want -1", code)
+	}
+	// builds come first, followed by the summary
+	builds := strings.Split(strings.Split(out.String(), "\n\n")[0], "\n")
+	for _, line := range builds {
+		if !strings.HasPrefix(line, "app | ") && !strings.HasPrefix(line, "lib | ") {
+			t.Errorf("output: got %q want it prefixed", line)
+		}
+	}
+	if !strings.Contains(out.String(), "lib | "+filepath.Join(pwd, "lib", ".gm.toml")+": Invalid value 'bogus'") {
+		t.Errorf("output: got %q want the lib error", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(pwd, "app", "args.txt")); err == nil {
+		t.Error("app: got executed, want -gc to stop it")
+	}
+}
+
+func TestFanOutReportsBuildsThatDoNotStart(t *testing.T) {
+	// given:
+	home := t.TempDir()
+	pwd := t.TempDir()
+	os.WriteFile(filepath.Join(home, ".gm.toml"), []byte("[general]\ntrust = \"always\"\n"), 0644)
+	for _, dir := range []string{"app", "lib"} {
+		os.Mkdir(filepath.Join(pwd, dir), 0755)
+	}
+	os.WriteFile(filepath.Join(pwd, "app", "settings.gradle"), []byte(""), 0644)
+	// the interpreter of the wrapper is missing, thus the build never starts
+	os.WriteFile(filepath.Join(pwd, "app", "gradlew"), []byte("#!/missing/sh\n"), 0755)
+	os.WriteFile(filepath.Join(pwd, "lib", "pom.xml"), []byte(""), 0644)
+	os.WriteFile(filepath.Join(pwd, "lib", "mvnw"), []byte("#!/bin/sh\n"), 0755)
+
+	out := &bytes.Buffer{}
+	context := testContext{
+		quiet:      true,
+		workingDir: pwd,
+		homeDir:    home,
+		stdout:     out}
+
+	// when:
+	args := ParseArgs([]string{"-gf", "build"})
+	code := FanOut(context, &args)
+
+	// then:
+	if code != -1 {
+		t.Errorf("exit code: got %d want -1", code)
+	}
+	if !strings.Contains(out.String(), "app | fork/exec "+filepath.Join(pwd, "app", "gradlew")) {
+		t.Errorf("output: got %q want the app error", out.String())
+	}
+	if !strings.Contains(out.String(), "1 of 2 projects failed") {
+		t.Errorf("output: got %q want app to fail", out.String())
+	}
+}
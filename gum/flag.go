@@ -33,7 +33,19 @@ func (a *ParsedArgs) HasGumFlag(flag string) bool {
 	return ok
 }
 
-var gumFlags = []string{"ga", "gb", "gc", "gd", "gg", "gh", "gj", "gm", "gn", "gq", "gr", "gv"}
+// Copies the parsed args, tool finders consume the flags they recognize
+func (a *ParsedArgs) copy() *ParsedArgs {
+	c := &ParsedArgs{
+		Gum:  make(map[string]struct{}, len(a.Gum)),
+		Tool: append(make([]string, 0, len(a.Tool)), a.Tool...),
+		Args: append(make([]string, 0, len(a.Args)), a.Args...)}
+	for k, v := range a.Gum {
+		c.Gum[k] = v
+	}
+	return c
+}
+
+var gumFlags = []string{"ga", "gb", "gc", "gd", "gf", "gg", "gh", "gi", "gj", "gl", "gm", "gn", "go", "gp", "gq", "gr", "gs", "gt", "gv", "gw", "gx", "gz", "g-allow", "g-deny"}
 
 // ParseArgs parses input args and separates them between Gum, Tool, and Args
 func ParseArgs(args []string) ParsedArgs {
@@ -167,3 +179,12 @@ func replaceArgs(args []string, replacements map[string]string, allowsSubMatch b
 
 	return nargs
 }
+
+func removeFirstNonFlagArg(args []string) []string {
+	for i, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			return shrinkSlice(args, i, 1)
+		}
+	}
+	return args
+}
@@ -19,12 +19,57 @@ package gum
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
+var gradleJavaReleasePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`JavaLanguageVersion\.of\(\s*["']?(\d+)`),
+	regexp.MustCompile(`jvmToolchain\(\s*(\d+)`)}
+
+var gradleIncludePattern = regexp.MustCompile(`^include\b\s*\(?`)
+var gradleQuotedPattern = regexp.MustCompile(`["']([^"']+)["']`)
+var gradleProjectDirPattern = regexp.MustCompile(`project\(\s*["']([^"']+)["']\s*\)\.projectDir\s*=\s*(?:file\s*\(|new\s+File\s*\(\s*(?:settingsDir|rootDir|rootProject\.projectDir)\s*,)\s*["']([^"']+)["']`)
+
+// Gradle options whose value is given as the next argument
+var gradleValueOptions = map[string]bool{
+	"--tests":             true,
+	"--console":           true,
+	"--warning-mode":      true,
+	"--priority":          true,
+	"-I":                  true,
+	"--init-script":       true,
+	"-g":                  true,
+	"--gradle-user-home":  true,
+	"--project-cache-dir": true,
+	"--include-build":     true,
+	"--configuration":     true,
+	"--dependency":        true,
+	"--args":              true,
+	// wrapper task
+	"--gradle-version":                 true,
+	"--distribution-type":              true,
+	"--gradle-distribution-url":        true,
+	"--gradle-distribution-sha256-sum": true,
+	"--network-timeout":                true,
+	// init task
+	"--type":              true,
+	"--dsl":               true,
+	"--project-name":      true,
+	"--package":           true,
+	"--test-framework":    true,
+	"--java-version":      true,
+	"--insecure-protocol": true}
+
+// Tasks that only exist in the root project thus are never qualified
+var gradleRootTasks = map[string]bool{
+	"wrapper": true,
+	"init":    true}
+
 // GradleCommand defines an executable Gradle command
 type GradleCommand struct {
 	context              Context
@@ -39,21 +84,62 @@ type GradleCommand struct {
 	rootBuildFile        string
 	settingsFile         string
 	explicitSettingsFile string
+	projectPath          string
+	affectedPaths        []string
+	executableSource     string
+	java                 *javaRuntime
+	wrapper              *wrapperVerification
+	version              *toolVersion
 }
 
 // Execute executes the given command
 func (c GradleCommand) Execute() int {
+	withheld := c.config.withheld != nil
+	if !trustProject(c.context, c.config, c.wrappers()...) {
+		c.context.Exit(-1)
+	}
+	if withheld && c.executableSource != "wrapper" {
+		if executable, source, err := findGradleExec(c.context, c.config); err == nil {
+			c.executable = executable
+			c.executableSource = source
+		}
+	}
 	c.doConfigureGradle()
 	return c.doExecuteGradle()
 }
 
+// Project local wrappers that must be trusted before execution
+func (c *GradleCommand) wrappers() []string {
+	if c.executableSource == "wrapper" {
+		return resolveGradleWrapperFiles(c.executable)
+	}
+	return nil
+}
+
+// Warns when the build runs from a settings file because no build file was found
+func (c *GradleCommand) warnNoBuildFile() {
+	if c.config.general.quiet || len(c.buildFile) > 0 || len(c.explicitBuildFile) > 0 || len(c.explicitProjectDir) > 0 {
+		return
+	}
+
+	if len(c.explicitSettingsFile) > 0 {
+		fmt.Fprintf(c.context.GetStdout(), "Did not find a suitable Gradle build file but %s is specified", c.explicitSettingsFile)
+		fmt.Fprintln(c.context.GetStdout())
+	} else if len(c.settingsFile) > 0 {
+		fmt.Fprintf(c.context.GetStdout(), "Did not find a suitable Gradle build file but found %s", c.settingsFile)
+		fmt.Fprintln(c.context.GetStdout())
+	}
+}
+
 func (c *GradleCommand) doConfigureGradle() {
 	c.context.CheckIsExecutable(c.executable)
+	c.warnNoBuildFile()
 
 	args := make([]string, 0)
 
 	banner := make([]string, 0)
 	banner = append(banner, "Using gradle at '"+c.executable+"'")
+	nearest := c.args.HasGumFlag("gn")
 	debug := c.args.HasGumFlag("gd")
 	skipReplace := c.args.HasGumFlag("gr")
 
@@ -64,6 +150,15 @@ func (c *GradleCommand) doConfigureGradle() {
 		c.config.gradle.setReplace(!skipReplace)
 	}
 	c.debugConfig()
+	c.java = resolveJavaRuntime(c.context, c.config)
+	release, source := findGradleRequiredJava(c.explicitBuildFile, c.rootBuildFile, c.buildFile)
+	if !c.java.require(c.context, c.config, release, source) {
+		c.context.Exit(-1)
+	}
+	if c.executableSource == "wrapper" {
+		c.wrapper = verifyGradleWrapper(c.context, c.config, filepath.Dir(c.executable))
+	}
+	c.resolveGradleVersion()
 	otargs := c.args.Tool
 	oargs := c.args.Args
 	rtargs, rargs := replaceGradleTasks(c.config, c.args)
@@ -91,6 +186,18 @@ func (c *GradleCommand) doConfigureGradle() {
 			// use parent dir of c.settingsFile
 			c.projectDir = filepath.Dir(c.settingsFile)
 			banner = append(banner, "with settings at '"+c.settingsFile+"':")
+
+			if c.config.gradle.scope && !nearest && len(c.settingsFile) > 0 {
+				projects := readGradleProjects(c.settingsFile)
+				c.projectPath = resolveGradleProjectPath(projects, c.context.GetWorkingDir())
+				rtargs = qualifyGradleTasks(rtargs, c.projectPath)
+				rargs = qualifyGradleTasks(rargs, c.projectPath)
+			}
+
+			if len(c.affectedPaths) > 0 {
+				rtargs = qualifyGradleTasksFor(rtargs, c.affectedPaths)
+				rargs = qualifyGradleTasksFor(rargs, c.affectedPaths)
+			}
 		}
 	}
 
@@ -99,64 +206,101 @@ func (c *GradleCommand) doConfigureGradle() {
 
 	c.debugGradle(otargs, oargs, rtargs, rargs)
 
+	if c.wrapper != nil && !c.wrapper.report(c.context, c.config, c.executable) {
+		c.context.Exit(-1)
+	}
+
 	if !c.config.general.quiet {
-		fmt.Println(strings.Join(banner, " "))
+		fmt.Fprintln(c.context.GetStdout(), strings.Join(banner, " "))
 	}
 }
 
 func (c *GradleCommand) doExecuteGradle() int {
 	cmd := exec.Command(c.executable, c.args.Args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	currentDir, _ := filepath.Abs(c.context.GetWorkingDir())
+	cmd.Env = c.java.environment(c.context)
+	cmd.Stdout = c.context.GetStdout()
+	cmd.Stderr = c.context.GetStderr()
+	cmd.Dir = c.context.GetWorkingDir()
 	if len(c.projectDir) > 0 {
-		os.Chdir(c.projectDir)
-		defer func() {
-			os.Chdir(currentDir)
-		}()
+		cmd.Dir = c.projectDir
 	}
 	err := cmd.Run()
-	if err == nil {
-		return 0
+	var exerr *exec.ExitError
+	if errors.As(err, &exerr) {
+		return exerr.ExitCode()
 	}
+	if err != nil {
+		fmt.Fprintln(c.context.GetStderr(), err)
+		return -1
+	}
+	return 0
+}
 
-	var exerr *exec.ExitError
-	errors.As(err, &exerr)
-	return exerr.ExitCode()
+// Resolves the Gradle version the project expects and checks it against the system gradle (if any)
+func (c *GradleCommand) resolveGradleVersion() {
+	dir := c.rootDir
+	if len(dir) == 0 {
+		dir = c.context.GetWorkingDir()
+	}
+
+	c.version = findExpectedToolVersion(c.context, dir, filepath.Join("gradle", "wrapper", "gradle-wrapper.properties"), gradleDistributionVersionPattern, "gradle", c.config.gradle.version)
+	if c.executableSource != "wrapper" && len(c.version.expected) > 0 && c.config.gradle.mismatch != "off" {
+		c.version.detected = readToolVersion(c.executable, c.java.environment(c.context), gradleVersionOutputPattern)
+	}
+	if !c.version.check(c.context, c.config, "Gradle", c.config.gradle.mismatch) {
+		c.context.Exit(-1)
+	}
 }
 
 func (c *GradleCommand) debugConfig() {
 	if c.args.HasGumFlag("gc") {
-		c.config.print()
-		os.Exit(0)
+		c.config.print(c.context.GetStdout())
+		c.context.Exit(0)
 	}
 }
 
 func (c *GradleCommand) debugGradle(otargs []string, oargs []string, rtargs []string, rargs []string) {
 	if c.config.general.debug {
-		fmt.Println("nearest              = ", c.args.HasGumFlag("gn"))
-		fmt.Println("replace              = ", c.config.gradle.replace)
-		fmt.Println("pwd                  = ", c.context.GetWorkingDir())
-		fmt.Println("rootDir              = ", c.rootDir)
-		fmt.Println("rootBuildFile        = ", c.rootBuildFile)
+		fmt.Fprintln(c.context.GetStdout(), "nearest              = ", c.args.HasGumFlag("gn"))
+		fmt.Fprintln(c.context.GetStdout(), "replace              = ", c.config.gradle.replace)
+		fmt.Fprintln(c.context.GetStdout(), "scope                = ", c.config.gradle.scope)
+		fmt.Fprintln(c.context.GetStdout(), "pwd                  = ", c.context.GetWorkingDir())
+		fmt.Fprintln(c.context.GetStdout(), "executable           = ", c.executable)
+		fmt.Fprintln(c.context.GetStdout(), "executable source    = ", c.executableSource)
+		if c.wrapper != nil {
+			c.wrapper.debug(c.context, 20)
+		}
+		fmt.Fprintln(c.context.GetStdout(), "rootDir              = ", c.rootDir)
+		fmt.Fprintln(c.context.GetStdout(), "rootBuildFile        = ", c.rootBuildFile)
 		if len(c.projectDir) > 0 {
-			fmt.Println("projectDir           = ", c.projectDir)
+			fmt.Fprintln(c.context.GetStdout(), "projectDir           = ", c.projectDir)
+		}
+		fmt.Fprintln(c.context.GetStdout(), "buildFile            = ", c.buildFile)
+		fmt.Fprintln(c.context.GetStdout(), "settingsFile         = ", c.settingsFile)
+		if len(c.projectPath) > 0 {
+			fmt.Fprintln(c.context.GetStdout(), "projectPath          = ", c.projectPath)
+		}
+		if len(c.affectedPaths) > 0 {
+			fmt.Fprintln(c.context.GetStdout(), "affected projects    = ", c.affectedPaths)
+		}
+		fmt.Fprintln(c.context.GetStdout(), "explicitBuildFile    = ", c.explicitBuildFile)
+		fmt.Fprintln(c.context.GetStdout(), "explicitSettingsFile = ", c.explicitSettingsFile)
+		fmt.Fprintln(c.context.GetStdout(), "explicitProjectDir   = ", c.explicitProjectDir)
+		fmt.Fprintln(c.context.GetStdout(), "java                 = ", c.java)
+		fmt.Fprintln(c.context.GetStdout(), "expected version     = ", c.version)
+		if len(c.version.detected) > 0 {
+			fmt.Fprintln(c.context.GetStdout(), "detected version     = ", c.version.detected)
 		}
-		fmt.Println("buildFile            = ", c.buildFile)
-		fmt.Println("settingsFile         = ", c.settingsFile)
-		fmt.Println("explicitBuildFile    = ", c.explicitBuildFile)
-		fmt.Println("explicitSettingsFile = ", c.explicitSettingsFile)
-		fmt.Println("explicitProjectDir   = ", c.explicitProjectDir)
-		fmt.Println("original tool args   = ", otargs)
+		fmt.Fprintln(c.context.GetStdout(), "original tool args   = ", otargs)
 		if c.config.gradle.replace {
-			fmt.Println("replaced tool args   = ", rtargs)
+			fmt.Fprintln(c.context.GetStdout(), "replaced tool args   = ", rtargs)
 		}
-		fmt.Println("original args        = ", oargs)
+		fmt.Fprintln(c.context.GetStdout(), "original args        = ", oargs)
 		if c.config.gradle.replace {
-			fmt.Println("replaced args        = ", rargs)
+			fmt.Fprintln(c.context.GetStdout(), "replaced args        = ", rargs)
 		}
-		fmt.Println("actual args          = ", c.args.Args)
-		fmt.Println("")
+		fmt.Fprintln(c.context.GetStdout(), "actual args          = ", c.args.Args)
+		fmt.Fprintln(c.context.GetStdout(), "")
 	}
 }
 
@@ -168,11 +312,154 @@ func replaceGradleTasks(config *Config, args *ParsedArgs) ([]string, []string) {
 	return args.Tool, args.Args
 }
 
+// Qualifies task names with the given project path, i.e, test becomes :libs:core:test.
+// Flags, option values, and absolute task paths are left untouched.
+func qualifyGradleTasks(args []string, projectPath string) []string {
+	if len(projectPath) == 0 || projectPath == ":" {
+		return args
+	}
+
+	nargs := make([]string, 0)
+	value := false
+	for _, arg := range args {
+		if value {
+			value = false
+			nargs = append(nargs, arg)
+		} else if strings.HasPrefix(arg, "-") {
+			value = gradleValueOptions[arg]
+			nargs = append(nargs, arg)
+		} else if strings.HasPrefix(arg, ":") || gradleRootTasks[arg] {
+			nargs = append(nargs, arg)
+		} else {
+			nargs = append(nargs, projectPath+":"+arg)
+		}
+	}
+
+	return nargs
+}
+
+// Qualifies task names with each of the given project paths, i.e, test becomes :core:test :api:test.
+// Flags are kept once, excluded tasks and the options following a task are repeated per project.
+func qualifyGradleTasksFor(args []string, projectPaths []string) []string {
+	nargs := make([]string, 0)
+	for i, projectPath := range projectPaths {
+		qargs := qualifyGradleTasks(args, projectPath)
+		if i == 0 {
+			nargs = append(nargs, qargs...)
+			continue
+		}
+
+		for j := 0; j < len(qargs); j++ {
+			if qargs[j] == args[j] {
+				continue
+			}
+			if j > 0 && (args[j-1] == "-x" || args[j-1] == "--exclude-task") {
+				nargs = append(nargs, args[j-1], qargs[j])
+				continue
+			}
+
+			nargs = append(nargs, qargs[j])
+			for k := j + 1; k < len(qargs) && qargs[k] == args[k] && strings.HasPrefix(args[k], "--"); k++ {
+				nargs = append(nargs, args[k])
+				if gradleValueOptions[args[k]] && k+1 < len(args) {
+					k++
+					nargs = append(nargs, args[k])
+				}
+			}
+		}
+	}
+	return nargs
+}
+
+// Resolves the path of the project holding dir, i.e, :libs:core.
+// Returns ":" when dir belongs to the root project.
+func resolveGradleProjectPath(projects map[string]string, dir string) string {
+	projectPath := ":"
+	projectDir := ""
+
+	for path, pdir := range projects {
+		if (dir == pdir || strings.HasPrefix(dir, pdir+string(os.PathSeparator))) && len(pdir) > len(projectDir) {
+			projectPath = path
+			projectDir = pdir
+		}
+	}
+
+	return projectPath
+}
+
+// Reads the projects included by the given settings file, keyed by project path.
+// Understands include declarations in both Groovy and Kotlin DSL, and projectDir overrides.
+func readGradleProjects(settingsFile string) map[string]string {
+	projects := make(map[string]string)
+	rootdir := filepath.Dir(settingsFile)
+
+	data, err := ioutil.ReadFile(settingsFile)
+	if err != nil {
+		return projects
+	}
+
+	statement := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		if len(statement) == 0 && gradleIncludePattern.MatchString(line) {
+			statement = line
+		} else if len(statement) > 0 {
+			statement = statement + " " + line
+		}
+
+		if len(statement) > 0 && !isGradleStatementContinued(statement) {
+			for _, match := range gradleQuotedPattern.FindAllStringSubmatch(statement, -1) {
+				addGradleProject(projects, rootdir, match[1])
+			}
+			statement = ""
+		}
+
+		for _, match := range gradleProjectDirPattern.FindAllStringSubmatch(line, -1) {
+			path := normalizeGradleProjectPath(match[1])
+			projects[path] = filepath.Join(rootdir, filepath.FromSlash(match[2]))
+		}
+	}
+
+	return projects
+}
+
+// Checks if an include statement spans into the next line
+func isGradleStatementContinued(statement string) bool {
+	if strings.HasSuffix(statement, ",") {
+		return true
+	}
+	return strings.Count(statement, "(") > strings.Count(statement, ")")
+}
+
+// Adds the given project, and its implicit parents, using their default directories
+func addGradleProject(projects map[string]string, rootdir string, path string) {
+	path = normalizeGradleProjectPath(path)
+	segments := strings.Split(strings.TrimPrefix(path, ":"), ":")
+
+	for i := range segments {
+		p := ":" + strings.Join(segments[:i+1], ":")
+		if _, exists := projects[p]; !exists {
+			projects[p] = filepath.Join(rootdir, filepath.Join(segments[:i+1]...))
+		}
+	}
+}
+
+func normalizeGradleProjectPath(path string) string {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, ":") {
+		path = ":" + path
+	}
+	return path
+}
+
 // FindGradle finds and executes gradlew/gradle
 func FindGradle(context Context, args *ParsedArgs) *GradleCommand {
 	pwd := context.GetWorkingDir()
 
-	gradle, noGradle := findGradleExec(context)
 	explicitProjectDirSet, explicitProjectDir := findExplicitProjectDir(args)
 
 	gradlew, noWrapper := resolveGradleWrapperExecutable(context, args)
@@ -186,7 +473,10 @@ func FindGradle(context Context, args *ParsedArgs) *GradleCommand {
 		sf = explicitBuildFile
 	}
 
-	rootBuildFile, noRootBuildFile := findGradleRootFile(context, filepath.Join(pwd, ".."), args, sf)
+	rootBuildFile, noRootBuildFile := "", errors.New("Did not find root build file")
+	if parentdir, ok := resolveSearchParent(context, pwd); ok {
+		rootBuildFile, noRootBuildFile = findGradleRootFile(context, parentdir, args, sf)
+	}
 	rootdir := resolveGradleRootDir(context, explicitProjectDir, explicitBuildFile, explicitSettingsFile, buildFile, rootBuildFile, settingsFile)
 	config := ReadConfig(context, rootdir)
 	quiet := args.HasGumFlag("gq")
@@ -195,12 +485,17 @@ func FindGradle(context Context, args *ParsedArgs) *GradleCommand {
 		config.setQuiet(quiet)
 	}
 
+	gradle, gradleSource, noGradle := findGradleExec(context, config)
+
 	var executable string
+	var executableSource string
 	if noWrapper == nil {
 		executable = gradlew
+		executableSource = "wrapper"
 	} else if noGradle == nil {
 		warnNoGradleWrapper(context, config)
 		executable = gradle
+		executableSource = gradleSource
 	} else {
 		warnNoGradle(context, config)
 
@@ -215,6 +510,7 @@ func FindGradle(context Context, args *ParsedArgs) *GradleCommand {
 			context:            context,
 			config:             config,
 			executable:         executable,
+			executableSource:   executableSource,
 			args:               args,
 			rootDir:            rootdir,
 			explicitProjectDir: explicitProjectDir}
@@ -226,6 +522,7 @@ func FindGradle(context Context, args *ParsedArgs) *GradleCommand {
 				context:              context,
 				config:               config,
 				executable:           executable,
+				executableSource:     executableSource,
 				args:                 args,
 				rootDir:              rootdir,
 				explicitBuildFile:    explicitBuildFile,
@@ -235,6 +532,7 @@ func FindGradle(context Context, args *ParsedArgs) *GradleCommand {
 			context:           context,
 			config:            config,
 			executable:        executable,
+			executableSource:  executableSource,
 			args:              args,
 			rootDir:           rootdir,
 			explicitBuildFile: explicitBuildFile,
@@ -247,28 +545,20 @@ func FindGradle(context Context, args *ParsedArgs) *GradleCommand {
 
 	if noBuildFile != nil {
 		if explicitSettingsFileSet {
-			if !config.general.quiet {
-				fmt.Printf("Did not find a suitable Gradle build file but %s is specified", explicitSettingsFile)
-				fmt.Println()
-			}
 			return &GradleCommand{
 				context:              context,
 				config:               config,
 				executable:           executable,
+				executableSource:     executableSource,
 				args:                 args,
 				rootDir:              rootdir,
 				buildFile:            buildFile,
 				rootBuildFile:        rootBuildFile,
 				explicitSettingsFile: explicitSettingsFile}
-		} else if noSettings == nil {
-			if !config.general.quiet {
-				fmt.Printf("Did not find a suitable Gradle build file but found %s", settingsFile)
-				fmt.Println()
-			}
-		} else {
+		} else if noSettings != nil {
 			if context.IsExplicit() {
-				fmt.Println("No Gradle project found")
-				fmt.Println()
+				fmt.Fprintln(context.GetStdout(), "No Gradle project found")
+				fmt.Fprintln(context.GetStdout())
 				context.Exit(-1)
 			}
 			return nil
@@ -279,6 +569,7 @@ func FindGradle(context Context, args *ParsedArgs) *GradleCommand {
 		context:              context,
 		config:               config,
 		executable:           executable,
+		executableSource:     executableSource,
 		args:                 args,
 		rootDir:              rootdir,
 		buildFile:            buildFile,
@@ -323,52 +614,41 @@ func resolveGradleWrapperExecutable(context Context, args *ParsedArgs) (string,
 
 func warnNoGradleWrapper(context Context, config *Config) {
 	if !config.general.quiet && context.IsExplicit() {
-		fmt.Printf("No %s set up for this project. ", resolveGradleWrapperExec(context))
-		fmt.Println()
-		fmt.Println("Please consider setting one up.")
-		fmt.Println("(https://gradle.org/docs/current/userguide/gradle_wrapper.html)")
-		fmt.Println()
+		fmt.Fprintf(context.GetStdout(), "No %s set up for this project. ", resolveGradleWrapperExec(context))
+		fmt.Fprintln(context.GetStdout())
+		fmt.Fprintln(context.GetStdout(), "Please consider setting one up, e.g. with `gm -gw`.")
+		fmt.Fprintln(context.GetStdout(), "(https://gradle.org/docs/current/userguide/gradle_wrapper.html)")
+		fmt.Fprintln(context.GetStdout())
 	}
 }
 
 func warnNoGradle(context Context, config *Config) {
 	if !config.general.quiet && context.IsExplicit() {
-		fmt.Printf("No %s found in path. Please install Gradle.", resolveGradleExec(context))
-		fmt.Println()
-		fmt.Println("(https://gradle.org/docs/current/userguide/installation.html)")
-		fmt.Println()
+		fmt.Fprintf(context.GetStdout(), "No %s found in path. Please install Gradle.", resolveGradleExec(context))
+		fmt.Fprintln(context.GetStdout())
+		fmt.Fprintln(context.GetStdout(), "(https://gradle.org/docs/current/userguide/installation.html)")
+		fmt.Fprintln(context.GetStdout())
 	}
 }
 
 // Finds the gradle executable
-func findGradleExec(context Context) (string, error) {
-	gradle := resolveGradleExec(context)
-	paths := context.GetPaths()
-
-	for i := range paths {
-		name := filepath.Join(paths[i], gradle)
-		if context.FileExists(name) {
-			return filepath.Abs(name)
-		}
-	}
-
-	return "", errors.New(gradle + " not found")
+func findGradleExec(context Context, config *Config) (string, string, error) {
+	return locateExecutable(context, "gradle", "GRADLE_HOME", config.gradle.executable, resolveGradleExec(context))
 }
 
 // Finds the gradle wrapper (if it exists)
 func findGradleWrapperExec(context Context, dir string) (string, error) {
 	wrapper := resolveGradleWrapperExec(context)
-	parentdir := filepath.Join(dir, "..")
-
-	if parentdir == dir {
-		return "", errors.New(wrapper + " not found")
-	}
-
 	path := filepath.Join(dir, wrapper)
 	if context.FileExists(path) {
 		return filepath.Abs(path)
 	}
 
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New(wrapper + " not found")
+	}
+
 	return findGradleWrapperExec(context, parentdir)
 }
 
@@ -444,12 +724,6 @@ func findExplicitGradleSettingsFile(args *ParsedArgs) (bool, string) {
 // - ${basedir}.gradle
 // - ${basedir}.gradle.kts
 func findGradleBuildFile(context Context, dir string) (string, error) {
-	parentdir := filepath.Join(dir, "..")
-
-	if parentdir == dir {
-		return "", errors.New("Did not find Gradle build file")
-	}
-
 	var buildFiles [4]string
 	buildFiles[0] = "build.gradle"
 	buildFiles[1] = "build.gradle.kts"
@@ -463,18 +737,17 @@ func findGradleBuildFile(context Context, dir string) (string, error) {
 		}
 	}
 
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New("Did not find Gradle build file")
+	}
+
 	return findGradleBuildFile(context, parentdir)
 }
 
 // Finds settings.gradle(.kts)
 // Unless explicit -c settingsFile is given in args
 func findGradleSettingsFile(context Context, dir string) (string, error) {
-	parentdir := filepath.Join(dir, "..")
-
-	if parentdir == dir {
-		return "", errors.New("Did not find Gradle settings file")
-	}
-
 	var settingsFiles [2]string
 	settingsFiles[0] = "settings.gradle"
 	settingsFiles[1] = "settings.gradle.kts"
@@ -486,17 +759,16 @@ func findGradleSettingsFile(context Context, dir string) (string, error) {
 		}
 	}
 
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New("Did not find Gradle settings file")
+	}
+
 	return findGradleSettingsFile(context, parentdir)
 }
 
 // Finds the root build file
 func findGradleRootFile(context Context, dir string, args *ParsedArgs, settingsFile string) (string, error) {
-	parentdir := filepath.Join(dir, "..")
-
-	if parentdir == dir {
-		return "", errors.New("Did not find root build file")
-	}
-
 	var buildFiles [2]string
 	buildFiles[0] = "build.gradle"
 	buildFiles[1] = "build.gradle.kts"
@@ -508,6 +780,11 @@ func findGradleRootFile(context Context, dir string, args *ParsedArgs, settingsF
 		}
 	}
 
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New("Did not find root build file")
+	}
+
 	if len(settingsFile) > 0 {
 		settingsdir := filepath.Dir(settingsFile)
 		if len(parentdir) <= len(settingsdir) {
@@ -533,3 +810,24 @@ func resolveGradleExec(context Context) string {
 	}
 	return "gradle"
 }
+
+// Finds the minimum Java feature release declared by toolchains in the given build files,
+// returning it along with the file it was read from
+func findGradleRequiredJava(buildFiles ...string) (int, string) {
+	release := 0
+	source := ""
+
+	for _, buildFile := range buildFiles {
+		if len(buildFile) == 0 {
+			continue
+		}
+
+		r := findJavaRelease(buildFile, gradleJavaReleasePatterns, false)
+		if r > release {
+			release = r
+			source = buildFile
+		}
+	}
+
+	return release, source
+}
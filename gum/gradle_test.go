@@ -18,6 +18,7 @@ package gum
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -540,3 +541,168 @@ func TestGradleReplaceWithSubMatch(t *testing.T) {
 		t.Errorf("args: got :subproject:verify, want b:subproject:build")
 	}
 }
+
+func TestGradleQualifiesTasksWithProjectPath(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "multi-project"))
+	pwd := filepath.Join(root, "libs", "core", "src")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "compile", "--tests", "Foo", ":api:test"})
+	cmd := FindGradle(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	cmd.doConfigureGradle()
+	actual := strings.Join(cmd.args.Args, " ")
+	expected := ":libs:core:classes --tests Foo :api:test"
+	if actual != expected {
+		t.Errorf("args: got %s, want %s", actual, expected)
+	}
+	if cmd.projectPath != ":libs:core" {
+		t.Errorf("projectPath: got %s, want :libs:core", cmd.projectPath)
+	}
+}
+
+func TestQualifyGradleTasks(t *testing.T) {
+	var checks = []struct {
+		title, args, expected string
+	}{
+		{"Task", "test", ":core:test"},
+		{"QualifiedTask", ":api:test", ":api:test"},
+		{"ValueOption", "test --tests Foo", ":core:test --tests Foo"},
+		{"Args", "run --args --verbose", ":core:run --args --verbose"},
+		{"ArgsWithValue", "run --args=foo", ":core:run --args=foo"},
+		{"Wrapper", "wrapper --gradle-version 8.10", "wrapper --gradle-version 8.10"},
+		{"Init", "init --type java-library --dsl kotlin", "init --type java-library --dsl kotlin"},
+		{"ExcludedTask", "build -x test", ":core:build -x :core:test"},
+	}
+
+	for _, check := range checks {
+		t.Run(check.title, func(t *testing.T) {
+			actual := strings.Join(qualifyGradleTasks(strings.Fields(check.args), ":core"), " ")
+			if actual != check.expected {
+				t.Errorf("got %s, want %s", actual, check.expected)
+			}
+		})
+	}
+}
+
+func TestGradleQualifiesTasksWithProjectDirOverride(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "multi-project"))
+	pwd := filepath.Join(root, "modules", "api")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "test"})
+	cmd := FindGradle(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	cmd.doConfigureGradle()
+	actual := strings.Join(cmd.args.Args, " ")
+	if actual != ":api:test" {
+		t.Errorf("args: got %s, want :api:test", actual)
+	}
+}
+
+func TestGradleNearestSkipsProjectPath(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "multi-project"))
+	pwd := filepath.Join(root, "libs", "core")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "-gn", "test"})
+	cmd := FindGradle(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	cmd.doConfigureGradle()
+	actual := strings.Join(cmd.args.Args, " ")
+	if actual != "test" {
+		t.Errorf("args: got %s, want test", actual)
+	}
+}
+
+func TestGradleVersionMismatch(t *testing.T) {
+	// given:
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "version-mismatch"))
+	bin := filepath.Join(pwd, "bin")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "build"})
+	cmd := FindGradle(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	cmd.doConfigureGradle()
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "gradle")},
+		{"ExpectedVersion", cmd.version.expected, "8.10.2"},
+		{"ExpectedVersionSource", cmd.version.source, filepath.Join(pwd, "gradle", "wrapper", "gradle-wrapper.properties")},
+		{"DetectedVersion", cmd.version.detected, "7.6.4"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+
+	if cmd.version.check(cmd.context, cmd.config, "Gradle", "fail") {
+		t.Error("check: got accepted, want refused")
+	}
+	if !cmd.version.check(cmd.context, cmd.config, "Gradle", "warn") {
+		t.Error("check: got refused, want accepted")
+	}
+}
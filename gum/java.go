@@ -0,0 +1,522 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// javaRuntime defines the JDK a command should run with
+type javaRuntime struct {
+	// requested version, i.e, 21 or 21.0.2-tem
+	version string
+	// file or config key the version was read from
+	source string
+	// JAVA_HOME of the matching installation, empty if none matched
+	home string
+	// where the installation was found, i.e, sdkman or /usr/lib/jvm
+	origin string
+	// minimum feature release required by the build, 0 if unknown
+	required int
+	// file the required release was read from
+	requiredSource string
+}
+
+// Resolves the JDK requested by the project at the working directory.
+// The version is read from the nearest .java-version, .sdkmanrc, or .tool-versions file,
+// falling back to the version set in the [java] config section.
+func resolveJavaRuntime(context Context, config *Config) *javaRuntime {
+	runtime := &javaRuntime{}
+
+	runtime.version, runtime.source = findJavaVersion(context, context.GetWorkingDir())
+	if len(runtime.version) == 0 && len(config.java.version) > 0 {
+		runtime.version = config.java.version
+		runtime.source = "[java] version"
+	}
+
+	if len(runtime.version) == 0 {
+		return runtime
+	}
+
+	runtime.home, runtime.origin = findJavaInstallation(context, config, runtime.version)
+	if len(runtime.home) == 0 && !config.general.quiet {
+		fmt.Fprintf(context.GetStdout(), "No JDK matching %s (from %s) found. Using the current environment.", runtime.version, runtime.source)
+		fmt.Fprintln(context.GetStdout())
+	}
+
+	return runtime
+}
+
+// Resolves the environment of a child process running on this JDK.
+// Returns nil when no JDK was resolved, so that the child inherits the current environment.
+func (r *javaRuntime) environment(context Context) []string {
+	if len(r.home) == 0 {
+		return nil
+	}
+
+	bin := filepath.Join(r.home, "bin")
+	env := make([]string, 0)
+	for _, entry := range os.Environ() {
+		kv := strings.SplitN(entry, "=", 2)
+		if kv[0] == "JAVA_HOME" {
+			continue
+		}
+		if len(kv) == 2 && (kv[0] == "PATH" || (context.IsWindows() && strings.EqualFold(kv[0], "PATH"))) {
+			entry = kv[0] + "=" + bin + string(os.PathListSeparator) + kv[1]
+		}
+		env = append(env, entry)
+	}
+
+	return append(env, "JAVA_HOME="+r.home)
+}
+
+// Resolves the given java/jshell executable against this JDK
+func (r *javaRuntime) executable(context Context, executable string) string {
+	if len(r.home) == 0 {
+		return executable
+	}
+
+	name := strings.TrimSuffix(filepath.Base(executable), ".exe")
+	if name != "java" && name != "jshell" {
+		return executable
+	}
+
+	path := filepath.Join(r.home, "bin", filepath.Base(executable))
+	if context.FileExists(path) {
+		return path
+	}
+	return executable
+}
+
+// Ensures the build does not run on a JDK older than the given feature release.
+// Depending on [java] mismatch, switches to a suitable installation failing when none is found ("switch"),
+// fails ("fail"), or only warns ("warn"). Returns false if the build should not run.
+func (r *javaRuntime) require(context Context, config *Config, release int, source string) bool {
+	if release == 0 || config.java.mismatch == "off" {
+		return true
+	}
+	r.required = release
+	r.requiredSource = source
+
+	current, home := r.resolveCurrentVersion(context)
+	if len(current) > 0 && javaFeatureRelease(current) >= release {
+		return true
+	}
+
+	if config.java.mismatch == "switch" {
+		switchHome, origin := findJavaInstallationAtLeast(context, config, release)
+		if len(switchHome) > 0 {
+			r.home = switchHome
+			r.origin = origin
+			return true
+		}
+	}
+
+	if len(current) == 0 {
+		// the current JDK is unknown, let the build decide
+		return true
+	}
+
+	if config.java.mismatch == "warn" {
+		if !config.general.quiet {
+			fmt.Fprintf(context.GetStdout(), "WARNING: %s requires Java %d or later but the JDK at '%s' is %s.", source, release, home, current)
+			fmt.Fprintln(context.GetStdout())
+		}
+		return true
+	}
+
+	fmt.Fprintf(context.GetStdout(), "%s requires Java %d or later but the JDK at '%s' is %s.", source, release, home, current)
+	fmt.Fprintln(context.GetStdout())
+	if config.java.mismatch == "switch" {
+		fmt.Fprintln(context.GetStdout(), "No suitable JDK was found. Please install one or set [java] directories.")
+	}
+	fmt.Fprintln(context.GetStdout())
+	return false
+}
+
+// Resolves the version and home of the JDK the build would run with
+func (r *javaRuntime) resolveCurrentVersion(context Context) (string, string) {
+	home := r.home
+	if len(home) == 0 {
+		home = context.GetEnv("JAVA_HOME")
+	}
+	if len(home) == 0 {
+		for _, path := range context.GetPaths() {
+			java := filepath.Join(path, resolveJavaExec(context))
+			if isRegularFile(java) {
+				java, _ = filepath.EvalSymlinks(java)
+				home = filepath.Dir(filepath.Dir(java))
+				break
+			}
+		}
+	}
+	if len(home) == 0 {
+		return "", ""
+	}
+
+	return normalizeJavaVersion(readJavaReleaseVersion(home, "")), home
+}
+
+func (r *javaRuntime) String() string {
+	parts := make([]string, 0)
+	if len(r.version) > 0 {
+		parts = append(parts, r.version+" (from "+r.source+")")
+	}
+	if r.required > 0 {
+		parts = append(parts, "requires "+strconv.Itoa(r.required)+"+ (from "+r.requiredSource+")")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+
+	if len(r.home) > 0 {
+		parts = append(parts, "at "+r.home+" ("+r.origin+")")
+	} else {
+		parts = append(parts, "using the current environment")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Finds the highest feature release in the given file matching any of the patterns.
+// Each pattern must capture the version in its first group.
+func findJavaRelease(file string, patterns []*regexp.Regexp, properties bool) int {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return 0
+	}
+
+	content := string(data)
+	release := 0
+	for _, pattern := range patterns {
+		for _, match := range pattern.FindAllStringSubmatch(content, -1) {
+			value := strings.TrimSpace(match[1])
+			if properties && strings.HasPrefix(value, "${") && strings.HasSuffix(value, "}") {
+				// resolve a property declared in the same file, i.e, ${java.version}
+				name := regexp.QuoteMeta(value[2 : len(value)-1])
+				property := regexp.MustCompile(`<` + name + `>\s*([^<\s]+)\s*</` + name + `>`).FindStringSubmatch(content)
+				if property == nil {
+					continue
+				}
+				value = property[1]
+			}
+			r := javaFeatureRelease(normalizeJavaVersion(value))
+			if r > release {
+				release = r
+			}
+		}
+	}
+
+	return release
+}
+
+// Finds the nearest Java version file and reads the version from it.
+// Checks the following files in order:
+// - .java-version
+// - .sdkmanrc
+// - .tool-versions
+func findJavaVersion(context Context, dir string) (string, string) {
+	var versionFiles [3]string
+	versionFiles[0] = ".java-version"
+	versionFiles[1] = ".sdkmanrc"
+	versionFiles[2] = ".tool-versions"
+
+	for i := range versionFiles {
+		path := filepath.Join(dir, versionFiles[i])
+		if !context.FileExists(path) {
+			continue
+		}
+		version := readJavaVersion(path)
+		if len(version) > 0 {
+			return version, path
+		}
+	}
+
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", ""
+	}
+
+	return findJavaVersion(context, parentdir)
+}
+
+// Reads the Java version from the given version file
+func readJavaVersion(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	name := filepath.Base(path)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		switch name {
+		case ".java-version":
+			return line
+		case ".sdkmanrc":
+			// java=21.0.2-tem
+			kv := strings.SplitN(line, "=", 2)
+			if len(kv) == 2 && strings.TrimSpace(kv[0]) == "java" {
+				return strings.TrimSpace(kv[1])
+			}
+		case ".tool-versions":
+			// java temurin-21.0.2+13.0.LTS
+			fields := strings.Fields(line)
+			if len(fields) > 1 && fields[0] == "java" {
+				return fields[1]
+			}
+		}
+	}
+
+	return ""
+}
+
+// javaInstallation defines a JDK found on the local filesystem
+type javaInstallation struct {
+	home    string
+	name    string
+	version string
+}
+
+// Finds the installation matching the given version.
+// An installation whose name equals the version wins, i.e, 21.0.2-tem for SDKMAN.
+// Otherwise the highest installation whose version starts with the requested version is chosen.
+func findJavaInstallation(context Context, config *Config, version string) (string, string) {
+	requested := normalizeJavaVersion(version)
+
+	return selectJavaInstallation(context, config, func(candidate javaInstallation, match javaInstallation) bool {
+		if candidate.name == version {
+			return match.name != version
+		}
+		if len(requested) == 0 || (candidate.version != requested && !strings.HasPrefix(candidate.version, requested+".")) {
+			return false
+		}
+		return len(match.home) == 0 || (match.name != version && compareVersions(candidate.version, match.version) > 0)
+	})
+}
+
+// Finds the installation closest to the given feature release, i.e, 21 when 17 is required
+// and both 21 and 24 are installed.
+func findJavaInstallationAtLeast(context Context, config *Config, release int) (string, string) {
+	return selectJavaInstallation(context, config, func(candidate javaInstallation, match javaInstallation) bool {
+		if javaFeatureRelease(candidate.version) < release {
+			return false
+		}
+		return len(match.home) == 0 || compareVersions(candidate.version, match.version) < 0
+	})
+}
+
+// Selects an installation, returning its home and origin.
+// The better function tells if a candidate is better than the current match, which is empty at first.
+// Checks the following locations in order, stopping at the first location with a match:
+// - [java] directories
+// - Maven toolchains (~/.m2/toolchains.xml)
+// - SDKMAN ($SDKMAN_DIR/candidates/java)
+// - asdf ($ASDF_DATA_DIR/installs/java)
+// - mise ($MISE_DATA_DIR/installs/java)
+// - system directories (/usr/lib/jvm, /Library/Java/JavaVirtualMachines)
+func selectJavaInstallation(context Context, config *Config, better func(candidate javaInstallation, match javaInstallation) bool) (string, string) {
+	for _, location := range resolveJavaLocations(context, config) {
+		var installations []javaInstallation
+		if location[0] == "toolchains.xml" {
+			installations = readMavenToolchains(context, location[1])
+		} else {
+			installations = listJavaInstallations(context, location[1])
+		}
+
+		match := javaInstallation{}
+		for _, candidate := range installations {
+			if better(candidate, match) {
+				match = candidate
+			}
+		}
+		if len(match.home) > 0 {
+			return match.home, location[0]
+		}
+	}
+
+	return "", ""
+}
+
+// Resolves the locations holding JDK installations, as [origin, path] pairs
+func resolveJavaLocations(context Context, config *Config) [][2]string {
+	locations := make([][2]string, 0)
+	home := context.GetHomeDir()
+
+	for _, dir := range config.java.directories {
+		locations = append(locations, [2]string{"[java] directories", dir})
+	}
+
+	if len(home) > 0 {
+		locations = append(locations, [2]string{"toolchains.xml", filepath.Join(home, ".m2", "toolchains.xml")})
+	}
+
+	sdkman := context.GetEnv("SDKMAN_DIR")
+	if len(sdkman) == 0 && len(home) > 0 {
+		sdkman = filepath.Join(home, ".sdkman")
+	}
+	if len(sdkman) > 0 {
+		locations = append(locations, [2]string{"sdkman", filepath.Join(sdkman, "candidates", "java")})
+	}
+
+	asdf := context.GetEnv("ASDF_DATA_DIR")
+	if len(asdf) == 0 && len(home) > 0 {
+		asdf = filepath.Join(home, ".asdf")
+	}
+	if len(asdf) > 0 {
+		locations = append(locations, [2]string{"asdf", filepath.Join(asdf, "installs", "java")})
+	}
+
+	mise := context.GetEnv("MISE_DATA_DIR")
+	if len(mise) == 0 && len(home) > 0 {
+		mise = filepath.Join(home, ".local", "share", "mise")
+	}
+	if len(mise) > 0 {
+		locations = append(locations, [2]string{"mise", filepath.Join(mise, "installs", "java")})
+	}
+
+	if !context.IsWindows() {
+		locations = append(locations, [2]string{"/usr/lib/jvm", "/usr/lib/jvm"})
+		locations = append(locations, [2]string{"/Library/Java/JavaVirtualMachines", "/Library/Java/JavaVirtualMachines"})
+	}
+
+	return locations
+}
+
+// Lists the installations found inside dir
+func listJavaInstallations(context Context, dir string) []javaInstallation {
+	installations := make([]javaInstallation, 0)
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return installations
+	}
+
+	for _, entry := range entries {
+		home := filepath.Join(dir, entry.Name())
+		if isDirectory(filepath.Join(home, "Contents", "Home")) {
+			// macOS bundle layout
+			home = filepath.Join(home, "Contents", "Home")
+		}
+		if entry.Name() == "current" || !context.FileExists(filepath.Join(home, "bin", resolveJavaExec(context))) {
+			continue
+		}
+
+		installations = append(installations, javaInstallation{
+			home:    home,
+			name:    entry.Name(),
+			version: normalizeJavaVersion(readJavaReleaseVersion(home, entry.Name()))})
+	}
+
+	return installations
+}
+
+type mavenToolchains struct {
+	Toolchains []struct {
+		Type    string `xml:"type"`
+		Version string `xml:"provides>version"`
+		JdkHome string `xml:"configuration>jdkHome"`
+	} `xml:"toolchain"`
+}
+
+// Lists the JDK installations declared in the given Maven toolchains.xml
+func readMavenToolchains(context Context, path string) []javaInstallation {
+	installations := make([]javaInstallation, 0)
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return installations
+	}
+
+	var toolchains mavenToolchains
+	if xml.Unmarshal(data, &toolchains) != nil {
+		return installations
+	}
+
+	for _, toolchain := range toolchains.Toolchains {
+		home := strings.TrimSpace(toolchain.JdkHome)
+		if strings.TrimSpace(toolchain.Type) != "jdk" || !context.FileExists(filepath.Join(home, "bin", resolveJavaExec(context))) {
+			continue
+		}
+
+		version := readJavaReleaseVersion(home, "")
+		if len(version) == 0 {
+			version = toolchain.Version
+		}
+		installations = append(installations, javaInstallation{
+			home:    home,
+			name:    filepath.Base(home),
+			version: normalizeJavaVersion(version)})
+	}
+
+	return installations
+}
+
+// Reads JAVA_VERSION from the release file of the given installation,
+// falling back to the version found in the directory name
+func readJavaReleaseVersion(home string, name string) string {
+	data, err := ioutil.ReadFile(filepath.Join(home, "release"))
+	if err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.HasPrefix(line, "JAVA_VERSION=") {
+				return strings.Trim(strings.TrimPrefix(line, "JAVA_VERSION="), "\" \r")
+			}
+		}
+	}
+
+	return name
+}
+
+// Extracts the numeric part of a version, i.e, temurin-21.0.2+13 becomes 21.0.2.
+// Legacy 1.x versions are turned into x, i.e, 1.8.0_392 becomes 8.0.
+func normalizeJavaVersion(version string) string {
+	v := versionPattern.FindString(version)
+	if strings.HasPrefix(v, "1.") && len(v) > 2 {
+		return v[2:]
+	}
+	return v
+}
+
+// Resolves the feature release of a normalized version, i.e, 21 for 21.0.2
+func javaFeatureRelease(version string) int {
+	release, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+	if err != nil {
+		return 0
+	}
+	return release
+}
+
+// Resolves the java executable (OS dependent)
+func resolveJavaExec(context Context) string {
+	if context.IsWindows() {
+		return "java.exe"
+	}
+	return "java"
+}
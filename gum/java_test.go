@@ -0,0 +1,292 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJavaFromSdkmanrc(t *testing.T) {
+	// given:
+	sdkman, _ := filepath.Abs(filepath.Join("..", "tests", "java", "sdkman"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "java", "sdkmanrc"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		env:        map[string]string{"SDKMAN_DIR": sdkman}}
+
+	config := newConfig()
+	config.merge(nil)
+
+	// when:
+	java := resolveJavaRuntime(context, config)
+
+	// then:
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Version", java.version, "21.0.2-tem"},
+		{"Source", java.source, filepath.Join(pwd, ".sdkmanrc")},
+		{"Home", java.home, filepath.Join(sdkman, "candidates", "java", "21.0.2-tem")},
+		{"Origin", java.origin, "sdkman"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestJavaFromJavaVersionPicksHighestMatch(t *testing.T) {
+	// given:
+	jdks, _ := filepath.Abs(filepath.Join("..", "tests", "java", "jdks"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "java", "java-version", "child"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd}
+
+	config := newConfig()
+	config.merge(nil)
+	config.java.directories = []string{jdks}
+
+	// when:
+	java := resolveJavaRuntime(context, config)
+
+	// then:
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Version", java.version, "21"},
+		{"Source", java.source, filepath.Join(pwd, "..", ".java-version")},
+		{"Home", java.home, filepath.Join(jdks, "jdk-21.0.5")},
+		{"Origin", java.origin, "[java] directories"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestJavaFromToolVersions(t *testing.T) {
+	// given:
+	jdks, _ := filepath.Abs(filepath.Join("..", "tests", "java", "jdks"))
+	sdkman, _ := filepath.Abs(filepath.Join("..", "tests", "java", "sdkman"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "java", "tool-versions"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		env:        map[string]string{"SDKMAN_DIR": sdkman}}
+
+	config := newConfig()
+	config.merge(nil)
+	config.java.directories = []string{jdks}
+
+	// when:
+	java := resolveJavaRuntime(context, config)
+
+	// then:
+	if java.version != "temurin-17.0.10+7" {
+		t.Errorf("Version: got %s, want temurin-17.0.10+7", java.version)
+	}
+	if java.home != filepath.Join(sdkman, "candidates", "java", "17.0.10-tem") {
+		t.Errorf("Home: got %s, want the 17.0.10-tem SDKMAN candidate", java.home)
+	}
+
+	env := java.environment(context)
+	var javaHome, path string
+	for _, entry := range env {
+		if strings.HasPrefix(entry, "JAVA_HOME=") {
+			javaHome = strings.TrimPrefix(entry, "JAVA_HOME=")
+		} else if strings.HasPrefix(entry, "PATH=") {
+			path = strings.TrimPrefix(entry, "PATH=")
+		}
+	}
+	if javaHome != java.home {
+		t.Errorf("JAVA_HOME: got %s, want %s", javaHome, java.home)
+	}
+	if len(os.Getenv("PATH")) > 0 && !strings.HasPrefix(path, filepath.Join(java.home, "bin")+string(os.PathListSeparator)) {
+		t.Errorf("PATH: got %s, want it prefixed with %s", path, filepath.Join(java.home, "bin"))
+	}
+}
+
+func TestJavaRequiredReleaseFromMaven(t *testing.T) {
+	// given:
+	pom, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "java-release", "pom.xml"))
+	modules, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "jvm-config", "pom.xml"))
+
+	// when:
+	release, source := findMavenRequiredJava(testContext{}, pom)
+	modulesRelease, modulesSource := findMavenRequiredJava(testContext{}, modules)
+
+	// then:
+	if release != 21 || source != pom {
+		t.Errorf("release: got %d from %s, want 21 from %s", release, source, pom)
+	}
+	jvmConfig := filepath.Join(filepath.Dir(modules), ".mvn", "jvm.config")
+	if modulesRelease != 9 || modulesSource != jvmConfig {
+		t.Errorf("release: got %d from %s, want 9 from %s", modulesRelease, modulesSource, jvmConfig)
+	}
+}
+
+func TestJavaRequiredReleaseFromGradleToolchain(t *testing.T) {
+	// given:
+	buildFile, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "toolchain", "build.gradle.kts"))
+
+	// when:
+	release, source := findGradleRequiredJava("", buildFile)
+
+	// then:
+	if release != 21 || source != buildFile {
+		t.Errorf("release: got %d from %s, want 21 from %s", release, source, buildFile)
+	}
+}
+
+func TestJavaRequireSwitchesToSuitableJdk(t *testing.T) {
+	// given:
+	jdks, _ := filepath.Abs(filepath.Join("..", "tests", "java", "jdks"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "java-release"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		env:        map[string]string{"JAVA_HOME": filepath.Join(jdks, "jdk-11.0.22")}}
+
+	config := newConfig()
+	config.merge(nil)
+	config.java.directories = []string{jdks}
+
+	// when:
+	java := resolveJavaRuntime(context, config)
+	ok := java.require(context, config, 21, "pom.xml")
+
+	// then:
+	if !ok {
+		t.Error("Expected to switch JDKs but failed")
+	}
+	if java.home != filepath.Join(jdks, "jdk-21.0.3") {
+		t.Errorf("Home: got %s, want %s", java.home, filepath.Join(jdks, "jdk-21.0.3"))
+	}
+}
+
+func TestJavaRequireFailsWhenJdkIsTooOld(t *testing.T) {
+	// given:
+	jdks, _ := filepath.Abs(filepath.Join("..", "tests", "java", "jdks"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "java-release"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		env:        map[string]string{"JAVA_HOME": filepath.Join(jdks, "jdk-11.0.22")}}
+
+	config := newConfig()
+	config.merge(nil)
+	config.java.mismatch = "fail"
+
+	// when:
+	java := resolveJavaRuntime(context, config)
+	ok := java.require(context, config, 17, "pom.xml")
+	satisfied := java.require(context, config, 11, "pom.xml")
+
+	// then:
+	if ok {
+		t.Error("Expected Java 11 to be rejected when 17 is required")
+	}
+	if !satisfied {
+		t.Error("Expected Java 11 to be accepted when 11 is required")
+	}
+	if len(java.home) > 0 {
+		t.Errorf("Home: got %s, want the current environment", java.home)
+	}
+}
+
+func TestJavaRequireWarns(t *testing.T) {
+	// given:
+	jdks, _ := filepath.Abs(filepath.Join("..", "tests", "java", "jdks"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "java-release"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		env:        map[string]string{"JAVA_HOME": filepath.Join(jdks, "jdk-11.0.22")}}
+
+	config := newConfig()
+	config.merge(nil)
+	config.java.directories = []string{jdks}
+	config.java.mismatch = "warn"
+
+	// when:
+	java := resolveJavaRuntime(context, config)
+	ok := java.require(context, config, 21, "pom.xml")
+
+	// then:
+	if !ok {
+		t.Error("Expected Java 11 to be accepted with a warning when 21 is required")
+	}
+	if len(java.home) > 0 {
+		t.Errorf("Home: got %s, want the current environment", java.home)
+	}
+}
+
+func TestJavaRequireFailsWhenNoJdkToSwitchTo(t *testing.T) {
+	// given:
+	jdks, _ := filepath.Abs(filepath.Join("..", "tests", "java", "jdks"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "java-release"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		env:        map[string]string{"JAVA_HOME": filepath.Join(jdks, "jdk-11.0.22")}}
+
+	config := newConfig()
+	config.merge(nil)
+	config.java.directories = []string{jdks}
+
+	// when:
+	java := resolveJavaRuntime(context, config)
+	ok := java.require(context, config, 99, "pom.xml")
+
+	// then:
+	if config.java.mismatch != "switch" {
+		t.Errorf("Mismatch: got %s, want switch", config.java.mismatch)
+	}
+	if ok {
+		t.Error("Expected Java 11 to be rejected when 99 is required and no JDK matches")
+	}
+}
@@ -20,9 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"os"
 	"os/exec"
-	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -37,6 +35,9 @@ const JshExt = ".jsh"
 // JarExt the .jar file extension
 const JarExt = ".jar"
 
+// jbangSourceExtensions supported source extensions in default discovery order
+var jbangSourceExtensions = []string{JavaExt, JshExt, JarExt}
+
 // JbangCommand defines an executable Jbang command
 type JbangCommand struct {
 	context            Context
@@ -45,76 +46,160 @@ type JbangCommand struct {
 	args               *ParsedArgs
 	sourceFile         string
 	explicitSourceFile string
+	fallback           bool
+	javaOptions        []string
+	executableSource   string
+	java               *javaRuntime
 }
 
 // Execute executes the given command
 func (c JbangCommand) Execute() int {
+	withheld := c.config.withheld != nil
+	if !trustProject(c.context, c.config, c.wrappers()...) {
+		c.context.Exit(-1)
+	}
+	if withheld && c.executableSource != "wrapper" {
+		if executable, source, err := findJbangExec(c.context, c.config); err == nil {
+			c.executable = executable
+			c.executableSource = source
+		}
+	}
 	c.doConfigureJbang()
 	return c.doExecuteJbang()
 }
 
+// Project local wrappers that must be trusted before execution
+func (c *JbangCommand) wrappers() []string {
+	if c.executableSource == "wrapper" {
+		return []string{c.executable}
+	}
+	return nil
+}
+
 func (c *JbangCommand) doConfigureJbang() {
 	c.context.CheckIsExecutable(c.executable)
 
 	args := make([]string, 0)
 
-	banner := make([]string, 0)
-	banner = append(banner, "Using jbang at '"+c.executable+"'")
-
 	debug := c.args.HasGumFlag("gd")
 
 	if debug {
 		c.config.setDebug(debug)
 	}
 	c.debugConfig()
+	c.java = resolveJavaRuntime(c.context, c.config)
+
+	file := c.sourceFile
+	if len(c.explicitSourceFile) > 0 {
+		file = c.explicitSourceFile
+	}
+	if c.fallback {
+		var deps []string
+		c.javaOptions, deps = readJbangDirectives(file)
+		warnJbangFallback(c.context, c.config, c.executable, file, deps)
+	}
+
+	banner := make([]string, 0)
+	if c.fallback {
+		c.executable = c.java.executable(c.context, c.executable)
+		banner = append(banner, "Using "+filepath.Base(c.executable)+" at '"+c.executable+"'")
+	} else {
+		banner = append(banner, "Using jbang at '"+c.executable+"'")
+	}
 	oargs := c.args.Args
+	pargs := oargs
 
-	args = appendSafe(args, c.args.Tool)
+	if c.fallback {
+		args, pargs = c.configureSourceLauncher(args, oargs)
+	} else {
+		args = appendSafe(args, c.args.Tool)
+	}
 
-	if len(c.explicitSourceFile) > 0 {
+	if c.fallback {
+		banner = append(banner, "to run '"+file+"':")
+	} else if len(c.explicitSourceFile) > 0 {
 		banner = append(banner, "to run '"+c.explicitSourceFile+"':")
 	} else if len(c.sourceFile) > 0 {
 		args = append(args, c.sourceFile)
 		banner = append(banner, "to run '"+c.sourceFile+"':")
 	}
 
-	c.args.Args = appendSafe(args, oargs)
+	c.args.Args = appendSafe(args, pargs)
 
 	c.debugJbang(c.config, oargs)
 
 	if !c.config.general.quiet {
-		fmt.Println(strings.Join(banner, " "))
+		fmt.Fprintln(c.context.GetStdout(), strings.Join(banner, " "))
 	}
 }
 
+// Configures java/jshell to launch the source file directly.
+// Returns the launcher args and the remaining program args.
+func (c *JbangCommand) configureSourceLauncher(args []string, oargs []string) ([]string, []string) {
+	file := c.sourceFile
+	if len(c.explicitSourceFile) > 0 {
+		file = c.explicitSourceFile
+		oargs = removeFirstNonFlagArg(oargs)
+	}
+
+	jshell := strings.HasSuffix(file, JshExt)
+	for _, opt := range c.javaOptions {
+		if jshell {
+			args = append(args, "-R"+opt)
+		} else {
+			args = append(args, opt)
+		}
+	}
+	args = appendSafe(args, c.args.Tool)
+
+	if strings.HasSuffix(file, JarExt) {
+		args = append(args, "-jar")
+	}
+	args = append(args, file)
+
+	return args, oargs
+}
+
 func (c *JbangCommand) doExecuteJbang() int {
 	cmd := exec.Command(c.executable, c.args.Args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Env = c.java.environment(c.context)
+	cmd.Stdout = c.context.GetStdout()
+	cmd.Stderr = c.context.GetStderr()
 	err := cmd.Run()
 	var exerr *exec.ExitError
 	if errors.As(err, &exerr) {
 		return exerr.ExitCode()
 	}
+	if err != nil {
+		fmt.Fprintln(c.context.GetStderr(), err)
+		return -1
+	}
 	return 0
 }
 
 func (c *JbangCommand) debugConfig() {
 	if c.args.HasGumFlag("gc") {
-		c.config.print()
-		os.Exit(0)
+		c.config.print(c.context.GetStdout())
+		c.context.Exit(0)
 	}
 }
 
 func (c *JbangCommand) debugJbang(config *Config, oargs []string) {
 	if c.config.general.debug {
-		fmt.Println("discovery          = ", config.jbang.discovery)
-		fmt.Println("pwd                = ", c.context.GetWorkingDir())
-		fmt.Println("sourceFile         = ", c.sourceFile)
-		fmt.Println("explicitSourceFile = ", c.explicitSourceFile)
-		fmt.Println("original args      = ", oargs)
-		fmt.Println("actual args        = ", c.args.Args)
-		fmt.Println("")
+		fmt.Fprintln(c.context.GetStdout(), "discovery          = ", config.jbang.discovery)
+		fmt.Fprintln(c.context.GetStdout(), "fallback           = ", c.fallback)
+		fmt.Fprintln(c.context.GetStdout(), "executable source  = ", c.executableSource)
+		if c.fallback {
+			fmt.Fprintln(c.context.GetStdout(), "executable         = ", c.executable)
+			fmt.Fprintln(c.context.GetStdout(), "java options       = ", c.javaOptions)
+		}
+		fmt.Fprintln(c.context.GetStdout(), "java               = ", c.java)
+		fmt.Fprintln(c.context.GetStdout(), "pwd                = ", c.context.GetWorkingDir())
+		fmt.Fprintln(c.context.GetStdout(), "sourceFile         = ", c.sourceFile)
+		fmt.Fprintln(c.context.GetStdout(), "explicitSourceFile = ", c.explicitSourceFile)
+		fmt.Fprintln(c.context.GetStdout(), "original args      = ", oargs)
+		fmt.Fprintln(c.context.GetStdout(), "actual args        = ", c.args.Args)
+		fmt.Fprintln(c.context.GetStdout(), "")
 	}
 }
 
@@ -123,7 +208,6 @@ func FindJbang(context Context, args *ParsedArgs) *JbangCommand {
 	pwd := context.GetWorkingDir()
 
 	jbangw, noWrapper := findJbangWrapperExec(context, pwd)
-	jbang, noJbang := findJbangExec(context)
 	explicitSourceFileSet, explicitSourceFile := findExplicitJbangSourceFile(pwd, args.Args)
 
 	config := ReadConfig(context, pwd)
@@ -136,12 +220,27 @@ func FindJbang(context Context, args *ParsedArgs) *JbangCommand {
 		config.setQuiet(quiet)
 	}
 
+	file := sourceFile
+	if explicitSourceFileSet {
+		file = explicitSourceFile
+	}
+
+	jbang, jbangSource, noJbang := findJbangExec(context, config)
+
 	var executable string
+	var executableSource string
+	fallback := false
 	if noWrapper == nil {
 		executable = jbangw
+		executableSource = "wrapper"
 	} else if noJbang == nil {
 		warnNoJbangWrapper(context, config)
 		executable = jbang
+		executableSource = jbangSource
+	} else if launcher, noLauncher := findJbangFallbackExec(context, config, file); noLauncher == nil {
+		executable = launcher
+		executableSource = "PATH"
+		fallback = true
 	} else {
 		warnNoJbang(context, config)
 
@@ -156,25 +255,29 @@ func FindJbang(context Context, args *ParsedArgs) *JbangCommand {
 			context:            context,
 			config:             config,
 			executable:         executable,
+			executableSource:   executableSource,
 			args:               args,
-			explicitSourceFile: explicitSourceFile}
+			explicitSourceFile: explicitSourceFile,
+			fallback:           fallback}
 	}
 
 	if noSourceFile != nil {
 		if context.IsExplicit() {
-			fmt.Println("No jbang project found")
-			fmt.Println()
+			fmt.Fprintln(context.GetStdout(), "No jbang project found")
+			fmt.Fprintln(context.GetStdout())
 			context.Exit(-1)
 		}
 		return nil
 	}
 
 	return &JbangCommand{
-		context:    context,
-		config:     config,
-		executable: executable,
-		args:       args,
-		sourceFile: sourceFile}
+		context:          context,
+		config:           config,
+		executable:       executable,
+		executableSource: executableSource,
+		args:             args,
+		sourceFile:       sourceFile,
+		fallback:         fallback}
 }
 
 func resolveJbangRootDir(context Context,
@@ -189,53 +292,105 @@ func resolveJbangRootDir(context Context,
 
 func warnNoJbangWrapper(context Context, config *Config) {
 	if !config.general.quiet && context.IsExplicit() {
-		fmt.Printf("No %s set up for this project. ", resolveJbangWrapperExec(context))
-		fmt.Println()
-		fmt.Println("Please consider setting one up.")
-		fmt.Println("(https://github.com/jbangdev)")
-		fmt.Println()
+		fmt.Fprintf(context.GetStdout(), "No %s set up for this project. ", resolveJbangWrapperExec(context))
+		fmt.Fprintln(context.GetStdout())
+		fmt.Fprintln(context.GetStdout(), "Please consider setting one up.")
+		fmt.Fprintln(context.GetStdout(), "(https://github.com/jbangdev)")
+		fmt.Fprintln(context.GetStdout())
 	}
 }
 
 func warnNoJbang(context Context, config *Config) {
 	if !config.general.quiet && context.IsExplicit() {
-		fmt.Printf("No %s found in path. Please install jbang.", resolveJbangExec(context))
-		fmt.Println()
-		fmt.Println("(https://github.com/jbangdev)")
-		fmt.Println()
+		fmt.Fprintf(context.GetStdout(), "No %s found in path. Please install jbang.", resolveJbangExec(context))
+		fmt.Fprintln(context.GetStdout())
+		fmt.Fprintln(context.GetStdout(), "(https://github.com/jbangdev)")
+		fmt.Fprintln(context.GetStdout())
 	}
 }
 
-// Finds the jbang executable
-func findJbangExec(context Context) (string, error) {
-	jbang := resolveJbangExec(context)
-	paths := context.GetPaths()
-
-	for i := range paths {
-		name := filepath.Join(paths[i], jbang)
-		if context.FileExists(name) {
-			return filepath.Abs(name)
+func warnJbangFallback(context Context, config *Config, executable string, file string, deps []string) {
+	if !config.general.quiet {
+		fmt.Fprintf(context.GetStdout(), "No %s found. Falling back to %s.", resolveJbangExec(context), filepath.Base(executable))
+		fmt.Fprintln(context.GetStdout())
+		if len(deps) > 0 {
+			fmt.Fprintf(context.GetStdout(), "WARNING: %s declares //DEPS that %s cannot resolve:", filepath.Base(file), filepath.Base(executable))
+			fmt.Fprintln(context.GetStdout())
+			for _, dep := range deps {
+				fmt.Fprintln(context.GetStdout(), "  "+dep)
+			}
+			fmt.Fprintln(context.GetStdout(), "Please install jbang.")
+			fmt.Fprintln(context.GetStdout(), "(https://github.com/jbangdev)")
 		}
+		fmt.Fprintln(context.GetStdout())
+	}
+}
+
+// Finds java/jshell to launch a local source file when jbang is not available.
+// Requires Java 11+ for .java files; Java 22+ for multi-file source programs.
+func findJbangFallbackExec(context Context, config *Config, file string) (string, error) {
+	if !config.jbang.fallback {
+		return "", errors.New("jbang fallback is disabled")
+	}
+	if len(file) == 0 || isLaunchableURL(file) || isLaunchableDependency(file) {
+		return "", errors.New("Not a local source file")
 	}
 
-	return "", errors.New(jbang + " not found")
+	if strings.HasSuffix(file, JshExt) {
+		return findExecutable(context, "", "jshell")
+	} else if strings.HasSuffix(file, JavaExt) || strings.HasSuffix(file, JarExt) {
+		return findExecutable(context, "", "java")
+	}
+
+	return "", errors.New("Not a launchable source file")
 }
 
-// Finds the Jbang wrapper (if it exists)
-func findJbangWrapperExec(context Context, dir string) (string, error) {
-	wrapper := resolveJbangWrapperExec(context)
-	parentdir := filepath.Join(dir, "..")
+// Reads //JAVA_OPTIONS and //DEPS directives from the given source file
+func readJbangDirectives(file string) ([]string, []string) {
+	javaOptions := make([]string, 0)
+	deps := make([]string, 0)
 
-	if parentdir == dir {
-		return "", errors.New(wrapper + " not found")
+	if strings.HasSuffix(file, JarExt) {
+		return javaOptions, deps
+	}
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return javaOptions, deps
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "//JAVA_OPTIONS ") {
+			javaOptions = append(javaOptions, strings.Fields(line[len("//JAVA_OPTIONS "):])...)
+		} else if strings.HasPrefix(line, "//DEPS ") {
+			deps = append(deps, strings.Fields(line[len("//DEPS "):])...)
+		}
 	}
 
+	return javaOptions, deps
+}
+
+// Finds the jbang executable
+func findJbangExec(context Context, config *Config) (string, string, error) {
+	return locateExecutable(context, "jbang", "JBANG_HOME", config.jbang.executable, resolveJbangExec(context))
+}
+
+// Finds the Jbang wrapper (if it exists)
+func findJbangWrapperExec(context Context, dir string) (string, error) {
+	wrapper := resolveJbangWrapperExec(context)
 	path := filepath.Join(dir, wrapper)
-	if context.FileExists(path) {
+	// directories named after the wrapper are skipped
+	if context.FileExists(path) && !isDirectory(path) {
 		return filepath.Abs(path)
 	}
 
-	return "", errors.New(wrapper + " not found")
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New(wrapper + " not found")
+	}
+
+	return findJbangWrapperExec(context, parentdir)
 }
 
 func isLaunchableSource(source string) bool {
@@ -299,69 +454,7 @@ func findExplicitJbangSourceFile(pwd string, args []string) (bool, string) {
 
 // Finds the nearest source file
 func findJbangSourceFile(context Context, dir string, config *Config, args []string) (string, error) {
-	files, err := ioutil.ReadDir(dir)
-
-	if err != nil {
-		return "", err
-	}
-
-	choices := make(map[string]string)
-
-	for i := range files {
-		file := files[i]
-		if isLaunchableSourceFile(file.Name()) {
-			extension := path.Ext(file.Name())
-			if extension == "" {
-				continue
-			}
-			_, exists := choices[extension]
-			if !exists {
-				choices[extension] = file.Name()
-			}
-		}
-	}
-
-	var file string
-	exists := false
-	if len(config.jbang.discovery) == 3 {
-		for i := range config.jbang.discovery {
-			choice := strings.TrimSpace(strings.ToLower(config.jbang.discovery[i]))
-
-			switch choice {
-			case "java":
-				file, exists = choices[JavaExt]
-				break
-			case "jsh":
-				file, exists = choices[JshExt]
-				break
-			case "jar":
-				file, exists = choices[JarExt]
-				break
-			default:
-				fmt.Println("Unsupported extension: " + choice)
-				os.Exit(-1)
-			}
-
-			if exists {
-				break
-			}
-		}
-	} else {
-		file, exists = choices[JavaExt]
-		if !exists {
-			file, exists = choices[JshExt]
-		}
-		if !exists {
-			file, exists = choices[JarExt]
-		}
-	}
-
-	if exists {
-		f, err := filepath.Abs(file)
-		return filepath.Join(dir, filepath.Base(f)), err
-	}
-
-	return "", errors.New("Did not find a launchable source")
+	return findSourceFile(context, dir, config.jbang.discovery, jbangSourceExtensions)
 }
 
 // Resolves the jbangw executable (OS dependent)
@@ -273,3 +273,84 @@ func TestJbangWithoutExecutables(t *testing.T) {
 		t.Error("Expected a nil command but got something")
 	}
 }
+
+func TestJbangJavaFallbackToJava(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "jbang", "jdk-bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "jbang", "java-with-directives"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "foo"})
+	cmd := FindJbang(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "java")},
+		{"SourceFile", cmd.sourceFile, filepath.Join(pwd, "hello.java")},
+		{"ExplicitSourceFile", cmd.explicitSourceFile, ""},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+
+	cmd.doConfigureJbang()
+	expected := []string{"-Xmx256m", "-Dgreeting=hello", filepath.Join(pwd, "hello.java"), "foo"}
+	if len(cmd.args.Args) != len(expected) {
+		t.Errorf("args: got %s, want %s", cmd.args.Args, expected)
+		return
+	}
+	for i := range expected {
+		if cmd.args.Args[i] != expected[i] {
+			t.Errorf("args: got %s, want %s", cmd.args.Args, expected)
+		}
+	}
+}
+
+func TestJbangJshFallbackToJshellWithExplicitFile(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "jbang", "jdk-bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "jbang", "jsh-without-wrapper"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "zzz.jsh"})
+	cmd := FindJbang(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	if cmd.executable != filepath.Join(bin, "jshell") {
+		t.Errorf("Executable: got %s, want %s", cmd.executable, filepath.Join(bin, "jshell"))
+	}
+
+	cmd.doConfigureJbang()
+	if len(cmd.args.Args) != 1 || cmd.args.Args[0] != filepath.Join(pwd, "zzz.jsh") {
+		t.Errorf("args: got %s, want [%s]", cmd.args.Args, filepath.Join(pwd, "zzz.jsh"))
+	}
+}
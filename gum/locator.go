@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// Finds the executable of a system wide tool installation, returning its path and the source it was found at.
+// Checks the following locations in order:
+// - the executable configured for the tool, i.e, [maven] executable
+// - the tool's home environment variable, i.e, $MAVEN_HOME/bin
+// - SDKMAN ($SDKMAN_DIR/candidates/<tool>/current/bin)
+// - asdf ($ASDF_DATA_DIR/installs/<tool>/<version>/bin), highest version first
+// - mise ($MISE_DATA_DIR/installs/<tool>/<version>/bin), highest version first
+// - $PATH
+func locateExecutable(context Context, tool string, homeEnv string, configured string, executable string) (string, string, error) {
+	if len(configured) > 0 {
+		if context.FileExists(configured) {
+			path, err := filepath.Abs(configured)
+			return path, "[" + tool + "] executable", err
+		}
+		return "", "", errors.New(configured + " not found")
+	}
+
+	home := context.GetHomeDir()
+
+	if len(homeEnv) > 0 {
+		dir := context.GetEnv(homeEnv)
+		if len(dir) > 0 {
+			path := filepath.Join(dir, "bin", executable)
+			if context.FileExists(path) {
+				path, err := filepath.Abs(path)
+				return path, "$" + homeEnv, err
+			}
+		}
+	}
+
+	sdkman := context.GetEnv("SDKMAN_DIR")
+	if len(sdkman) == 0 && len(home) > 0 {
+		sdkman = filepath.Join(home, ".sdkman")
+	}
+	if len(sdkman) > 0 {
+		path := filepath.Join(sdkman, "candidates", tool, "current", "bin", executable)
+		if context.FileExists(path) {
+			path, err := filepath.Abs(path)
+			return path, "sdkman", err
+		}
+	}
+
+	asdf := context.GetEnv("ASDF_DATA_DIR")
+	if len(asdf) == 0 && len(home) > 0 {
+		asdf = filepath.Join(home, ".asdf")
+	}
+	if path := findInstalledExecutable(context, asdf, tool, executable); len(path) > 0 {
+		return path, "asdf", nil
+	}
+
+	mise := context.GetEnv("MISE_DATA_DIR")
+	if len(mise) == 0 && len(home) > 0 {
+		mise = filepath.Join(home, ".local", "share", "mise")
+	}
+	if path := findInstalledExecutable(context, mise, tool, executable); len(path) > 0 {
+		return path, "mise", nil
+	}
+
+	paths := context.GetPaths()
+	for i := range paths {
+		name := filepath.Join(paths[i], executable)
+		if context.FileExists(name) {
+			path, err := filepath.Abs(name)
+			return path, "PATH", err
+		}
+	}
+
+	return "", "", errors.New(executable + " not found")
+}
+
+// Finds the executable of the highest tool version installed by asdf or mise at dir
+func findInstalledExecutable(context Context, dir string, tool string, executable string) string {
+	if len(dir) == 0 {
+		return ""
+	}
+
+	entries, err := ioutil.ReadDir(filepath.Join(dir, "installs", tool))
+	if err != nil {
+		return ""
+	}
+
+	match := ""
+	matchVersion := ""
+	for _, entry := range entries {
+		path := filepath.Join(dir, "installs", tool, entry.Name(), "bin", executable)
+		if !context.FileExists(path) {
+			continue
+		}
+		version := versionPattern.FindString(entry.Name())
+		if len(match) == 0 || compareVersions(version, matchVersion) > 0 {
+			match = path
+			matchVersion = version
+		}
+	}
+
+	if len(match) > 0 {
+		match, _ = filepath.Abs(match)
+	}
+	return match
+}
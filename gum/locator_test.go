@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLocateExecutable(t *testing.T) {
+	// given:
+	base, _ := filepath.Abs(filepath.Join("..", "tests", "locator"))
+	bin := filepath.Join(base, "bin")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: base,
+		paths:      []string{bin},
+		env: map[string]string{
+			"MAVEN_HOME":    filepath.Join(base, "maven-home"),
+			"SDKMAN_DIR":    filepath.Join(base, "sdkman"),
+			"ASDF_DATA_DIR": filepath.Join(base, "asdf")}}
+
+	config := newConfig()
+	config.merge(nil)
+	config.jbang.executable = filepath.Join(base, "custom", "jbang")
+
+	// when:
+	mvn, mvnSource, _ := findMavenExec(context, config)
+	gradle, gradleSource, _ := findGradleExec(context, config)
+	ant, antSource, _ := findAntExec(context, config)
+	jbang, jbangSource, _ := findJbangExec(context, config)
+	mvnd, mvndSource, noMvnd := findMvndExec(context)
+
+	// then:
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Maven", mvn, filepath.Join(base, "maven-home", "bin", "mvn")},
+		{"Maven source", mvnSource, "$MAVEN_HOME"},
+		{"Gradle", gradle, filepath.Join(base, "sdkman", "candidates", "gradle", "current", "bin", "gradle")},
+		{"Gradle source", gradleSource, "sdkman"},
+		{"Ant", ant, filepath.Join(base, "asdf", "installs", "ant", "1.10.13", "bin", "ant")},
+		{"Ant source", antSource, "asdf"},
+		{"JBang", jbang, filepath.Join(base, "custom", "jbang")},
+		{"JBang source", jbangSource, "[jbang] executable"},
+		{"mvnd", mvnd, ""},
+		{"mvnd source", mvndSource, ""},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+
+	if noMvnd == nil {
+		t.Error("Expected mvnd not to be found")
+	}
+}
+
+func TestLocateExecutableFallsBackToPath(t *testing.T) {
+	// given:
+	base, _ := filepath.Abs(filepath.Join("..", "tests", "locator"))
+	bin := filepath.Join(base, "bin")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: base,
+		paths:      []string{bin}}
+
+	config := newConfig()
+	config.merge(nil)
+
+	// when:
+	mvn, source, _ := findMavenExec(context, config)
+
+	// then:
+	if mvn != filepath.Join(bin, "mvn") || source != "PATH" {
+		t.Errorf("Maven: got %s from %s, want %s from PATH", mvn, source, filepath.Join(bin, "mvn"))
+	}
+}
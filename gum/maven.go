@@ -17,14 +17,25 @@
 package gum
 
 import (
+	"encoding/xml"
 	"errors"
 	"fmt"
-	"os"
+	"io/ioutil"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
+var mavenJavaReleasePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`<maven\.compiler\.release>\s*([^<\s]+)\s*</maven\.compiler\.release>`),
+	regexp.MustCompile(`<maven\.compiler\.target>\s*([^<\s]+)\s*</maven\.compiler\.target>`),
+	regexp.MustCompile(`<release>\s*([^<\s]+)\s*</release>`)}
+
+// JVM options that require the module system
+var mavenJvmConfigModuleOptions = []string{"--add-opens", "--add-exports", "--add-modules", "--add-reads"}
+
 // MavenCommand defines an executable Maven command
 type MavenCommand struct {
 	context           Context
@@ -34,15 +45,72 @@ type MavenCommand struct {
 	buildFile         string
 	explicitBuildFile string
 	rootBuildFile     string
+	rootCandidates    []string
+	moduleSelector    string
+	affectedModules   []string
+	executableSource  string
+	executableReason  string
+	mavenExecutable   string
+	mavenSource       string
+	mavenReason       string
+	java              *javaRuntime
+	wrapper           *wrapperVerification
+	version           *toolVersion
+	mavenConfig       []string
 }
 
 // Execute executes the given command
 func (c MavenCommand) Execute() int {
+	withheld := c.config.withheld != nil
+	if !trustProject(c.context, c.config, c.wrappers()...) {
+		c.context.Exit(-1)
+	}
+	if withheld && c.mavenSource != "wrapper" {
+		if executable, source, err := findMavenExec(c.context, c.config); err == nil {
+			if !c.isMvnd() {
+				c.executable = executable
+				c.executableSource = source
+			}
+			c.mavenExecutable = executable
+			c.mavenSource = source
+		}
+	}
 	c.doConfigureMaven()
 	return c.doExecuteMaven()
 }
 
+// Project local wrappers that must be trusted before execution, including the one mvnd may fall back to
+func (c *MavenCommand) wrappers() []string {
+	if c.executableSource == "wrapper" {
+		return resolveMavenWrapperFiles(c.executable)
+	} else if c.mavenSource == "wrapper" {
+		return resolveMavenWrapperFiles(c.mavenExecutable)
+	}
+	return nil
+}
+
+func (c *MavenCommand) isMvnd() bool {
+	return strings.HasPrefix(filepath.Base(c.executable), "mvnd")
+}
+
+// mvnd does not read .mvn/maven.config thus its options are passed explicitly
+func (c *MavenCommand) resolveMavenConfig() []string {
+	dir := c.context.GetWorkingDir()
+	if len(c.explicitBuildFile) > 0 {
+		dir = filepath.Dir(c.explicitBuildFile)
+	} else if len(c.rootBuildFile) > 0 {
+		dir = filepath.Dir(c.rootBuildFile)
+	}
+
+	projectDir, err := findMavenProjectDir(c.context, dir)
+	if err != nil {
+		return nil
+	}
+	return readMavenConfig(projectDir)
+}
+
 func (c *MavenCommand) doConfigureMaven() {
+	c.checkMvndStatus()
 	c.context.CheckIsExecutable(c.executable)
 
 	args := make([]string, 0)
@@ -50,6 +118,7 @@ func (c *MavenCommand) doConfigureMaven() {
 	banner := make([]string, 0)
 	banner = append(banner, "Using maven at '"+c.executable+"'")
 	nearest := c.args.HasGumFlag("gn")
+	module := c.args.HasGumFlag("go")
 	debug := c.args.HasGumFlag("gd")
 	skipReplace := c.args.HasGumFlag("gr")
 
@@ -59,11 +128,28 @@ func (c *MavenCommand) doConfigureMaven() {
 	if skipReplace {
 		c.config.gradle.setReplace(!skipReplace)
 	}
+	if module {
+		c.config.maven.setScope("module")
+	}
 	c.debugConfig()
+	c.java = resolveJavaRuntime(c.context, c.config)
+	release, source := findMavenRequiredJava(c.context, c.explicitBuildFile, c.rootBuildFile, c.buildFile)
+	if !c.java.require(c.context, c.config, release, source) {
+		c.context.Exit(-1)
+	}
+	if c.executableSource == "wrapper" {
+		c.wrapper = verifyMavenWrapper(c.context, c.config, filepath.Dir(c.executable))
+	}
+	c.resolveMavenVersion()
 	otargs := c.args.Tool
 	oargs := c.args.Args
 	rtargs, rargs := replaceMavenGoals(c.config, c.args)
 
+	if c.isMvnd() {
+		c.mavenConfig = c.resolveMavenConfig()
+		args = append(args, c.mavenConfig...)
+	}
+
 	if len(c.explicitBuildFile) > 0 {
 		args = append(args, "-f")
 		args = append(args, c.explicitBuildFile)
@@ -76,6 +162,17 @@ func (c *MavenCommand) doConfigureMaven() {
 		args = append(args, "-f")
 		args = append(args, c.rootBuildFile)
 		banner = append(banner, "to run buildFile '"+c.rootBuildFile+"':")
+		// reactor projects selected by the user are left untouched
+		projectList := hasMavenProjectList(rtargs) || hasMavenProjectList(rargs)
+		if c.config.maven.scope == "module" && len(c.moduleSelector) > 0 && !projectList {
+			args = append(args, "-pl")
+			args = append(args, c.moduleSelector)
+			args = append(args, "-am")
+		} else if len(c.affectedModules) > 0 && !projectList {
+			args = append(args, "-pl")
+			args = append(args, strings.Join(c.affectedModules, ","))
+			args = append(args, "-amd")
+		}
 	}
 
 	args = appendSafe(args, rtargs)
@@ -83,48 +180,96 @@ func (c *MavenCommand) doConfigureMaven() {
 
 	c.debugMaven(otargs, oargs, rtargs, rargs)
 
+	if c.wrapper != nil && !c.wrapper.report(c.context, c.config, c.executable) {
+		c.context.Exit(-1)
+	}
+
 	if !c.config.general.quiet {
-		fmt.Println(strings.Join(banner, " "))
+		fmt.Fprintln(c.context.GetStdout(), strings.Join(banner, " "))
 	}
 }
 
 func (c *MavenCommand) doExecuteMaven() int {
 	cmd := exec.Command(c.executable, c.args.Args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Env = c.java.environment(c.context)
+	cmd.Stdout = c.context.GetStdout()
+	cmd.Stderr = c.context.GetStderr()
+	cmd.Dir = c.context.GetWorkingDir()
 	err := cmd.Run()
 	var exerr *exec.ExitError
 	if errors.As(err, &exerr) {
 		return exerr.ExitCode()
 	}
+	if err != nil {
+		fmt.Fprintln(c.context.GetStderr(), err)
+		return -1
+	}
 	return 0
 }
 
+// Resolves the Maven version the project expects and checks it against the system maven (if any)
+func (c *MavenCommand) resolveMavenVersion() {
+	dir := c.context.GetWorkingDir()
+	if len(c.rootBuildFile) > 0 {
+		dir = filepath.Dir(c.rootBuildFile)
+	}
+
+	c.version = findExpectedToolVersion(c.context, dir, filepath.Join(".mvn", "wrapper", "maven-wrapper.properties"), mavenDistributionVersionPattern, "maven", c.config.maven.version)
+	if c.executableSource != "wrapper" && !c.isMvnd() && len(c.version.expected) > 0 && c.config.maven.mismatch != "off" {
+		c.version.detected = readToolVersion(c.executable, c.java.environment(c.context), mavenVersionOutputPattern)
+	}
+	if !c.version.check(c.context, c.config, "Maven", c.config.maven.mismatch) {
+		c.context.Exit(-1)
+	}
+}
+
 func (c *MavenCommand) debugConfig() {
 	if c.args.HasGumFlag("gc") {
-		c.config.print()
-		os.Exit(0)
+		c.config.print(c.context.GetStdout())
+		c.context.Exit(0)
 	}
 }
 
 func (c *MavenCommand) debugMaven(otargs []string, oargs []string, rtargs []string, rargs []string) {
 	if c.config.general.debug {
-		fmt.Println("nearest            = ", c.args.HasGumFlag("gn"))
-		fmt.Println("replace            = ", c.config.maven.replace)
-		fmt.Println("pwd                = ", c.context.GetWorkingDir())
-		fmt.Println("rootBuildFile      = ", c.rootBuildFile)
-		fmt.Println("buildFile          = ", c.buildFile)
-		fmt.Println("explicitBuildFile  = ", c.explicitBuildFile)
-		fmt.Println("original tool args = ", otargs)
+		fmt.Fprintln(c.context.GetStdout(), "nearest            = ", c.args.HasGumFlag("gn"))
+		fmt.Fprintln(c.context.GetStdout(), "replace            = ", c.config.maven.replace)
+		fmt.Fprintln(c.context.GetStdout(), "scope              = ", c.config.maven.scope)
+		fmt.Fprintln(c.context.GetStdout(), "pwd                = ", c.context.GetWorkingDir())
+		fmt.Fprintln(c.context.GetStdout(), "executable         = ", c.executable)
+		fmt.Fprintln(c.context.GetStdout(), "executable source  = ", c.executableSource)
+		fmt.Fprintln(c.context.GetStdout(), "executable reason  = ", c.executableReason)
+		if len(c.mavenConfig) > 0 {
+			fmt.Fprintln(c.context.GetStdout(), "maven.config       = ", c.mavenConfig)
+		}
+		if c.wrapper != nil {
+			c.wrapper.debug(c.context, 18)
+		}
+		fmt.Fprintln(c.context.GetStdout(), "rootBuildFile      = ", c.rootBuildFile)
+		fmt.Fprintln(c.context.GetStdout(), "buildFile          = ", c.buildFile)
+		fmt.Fprintln(c.context.GetStdout(), "explicitBuildFile  = ", c.explicitBuildFile)
+		for _, candidate := range c.rootCandidates {
+			fmt.Fprintln(c.context.GetStdout(), "root candidate     = ", candidate)
+		}
+		fmt.Fprintln(c.context.GetStdout(), "module selector    = ", c.moduleSelector)
+		if len(c.affectedModules) > 0 {
+			fmt.Fprintln(c.context.GetStdout(), "affected modules   = ", c.affectedModules)
+		}
+		fmt.Fprintln(c.context.GetStdout(), "java               = ", c.java)
+		fmt.Fprintln(c.context.GetStdout(), "expected version   = ", c.version)
+		if len(c.version.detected) > 0 {
+			fmt.Fprintln(c.context.GetStdout(), "detected version   = ", c.version.detected)
+		}
+		fmt.Fprintln(c.context.GetStdout(), "original tool args = ", otargs)
 		if c.config.maven.replace {
-			fmt.Println("replaced tool args = ", rtargs)
+			fmt.Fprintln(c.context.GetStdout(), "replaced tool args = ", rtargs)
 		}
-		fmt.Println("original args      = ", oargs)
+		fmt.Fprintln(c.context.GetStdout(), "original args      = ", oargs)
 		if c.config.maven.replace {
-			fmt.Println("replaced args      = ", rargs)
+			fmt.Fprintln(c.context.GetStdout(), "replaced args      = ", rargs)
 		}
-		fmt.Println("actual args        = ", c.args.Args)
-		fmt.Println("")
+		fmt.Fprintln(c.context.GetStdout(), "actual args        = ", c.args.Args)
+		fmt.Fprintln(c.context.GetStdout(), "")
 	}
 }
 
@@ -136,17 +281,25 @@ func replaceMavenGoals(config *Config, args *ParsedArgs) ([]string, []string) {
 	return args.Tool, args.Args
 }
 
+// Checks if the given args already select reactor projects
+func hasMavenProjectList(args []string) bool {
+	for _, arg := range args {
+		if arg == "-pl" || arg == "--projects" || strings.HasPrefix(arg, "--projects=") {
+			return true
+		}
+	}
+	return false
+}
+
 // FindMaven finds and executes mvnw/mvn
 func FindMaven(context Context, args *ParsedArgs) *MavenCommand {
 	pwd := context.GetWorkingDir()
 
 	mvnw, noWrapper := findMavenWrapperExec(context, pwd)
-	mvn, noMaven := findMavenExec(context)
-	mvnd, noMvnd := findMvndExec(context)
 	explicitBuildFileSet, explicitBuildFile := findExplicitMavenBuildFile(args)
 
-	rootBuildFile, noRootBuildFile := findMavenRootFile(context, filepath.Join(pwd, ".."))
 	buildFile, noBuildFile := findMavenBuildFile(context, pwd)
+	rootBuildFile, rootCandidates, noRootBuildFile := findMavenRootFile(context, buildFile)
 	rootdir := resolveMavenRootDir(context, explicitBuildFile, buildFile, rootBuildFile)
 	config := ReadConfig(context, rootdir)
 	quiet := args.HasGumFlag("gq")
@@ -155,15 +308,27 @@ func FindMaven(context Context, args *ParsedArgs) *MavenCommand {
 		config.setQuiet(quiet)
 	}
 
-	var executable string
-	if config.maven.mvnd && noMvnd == nil {
-		executable = mvnd
-	} else if noWrapper == nil {
-		executable = mvnw
+	mvn, mvnSource, noMaven := findMavenExec(context, config)
+	mvnd, mvndSource, noMvnd := findMvndExec(context)
+
+	useMvnd, mvndReason := resolveMvndPolicy(config, args, noMvnd)
+
+	// Maven runs the build when mvnd does not, or when its daemon turns out to be unhealthy
+	var mavenExecutable string
+	var mavenSource string
+	var mavenReason string
+	if noWrapper == nil {
+		mavenExecutable = mvnw
+		mavenSource = "wrapper"
+		mavenReason = "project wrapper found"
 	} else if noMaven == nil {
-		warnNoMavenWrapper(context, config)
-		executable = mvn
-	} else {
+		if !useMvnd {
+			warnNoMavenWrapper(context, config)
+		}
+		mavenExecutable = mvn
+		mavenSource = mvnSource
+		mavenReason = "no project wrapper found"
+	} else if !useMvnd {
 		warnNoMaven(context, config)
 
 		if context.IsExplicit() {
@@ -172,11 +337,25 @@ func FindMaven(context Context, args *ParsedArgs) *MavenCommand {
 		return nil
 	}
 
+	executable := mavenExecutable
+	executableSource := mavenSource
+	executableReason := appendReason(mvndReason, mavenReason)
+	if useMvnd {
+		executable = mvnd
+		executableSource = mvndSource
+		executableReason = mvndReason
+	}
+
 	if explicitBuildFileSet {
 		return &MavenCommand{
 			context:           context,
 			config:            config,
 			executable:        executable,
+			executableSource:  executableSource,
+			executableReason:  executableReason,
+			mavenExecutable:   mavenExecutable,
+			mavenSource:       mavenSource,
+			mavenReason:       mavenReason,
 			args:              args,
 			explicitBuildFile: explicitBuildFile}
 	}
@@ -187,20 +366,106 @@ func FindMaven(context Context, args *ParsedArgs) *MavenCommand {
 
 	if noBuildFile != nil {
 		if context.IsExplicit() {
-			fmt.Println("No Maven project found")
-			fmt.Println()
+			fmt.Fprintln(context.GetStdout(), "No Maven project found")
+			fmt.Fprintln(context.GetStdout())
 			context.Exit(-1)
 		}
 		return nil
 	}
 
 	return &MavenCommand{
-		context:       context,
-		config:        config,
-		executable:    executable,
-		args:          args,
-		rootBuildFile: rootBuildFile,
-		buildFile:     buildFile}
+		context:          context,
+		config:           config,
+		executable:       executable,
+		executableSource: executableSource,
+		executableReason: executableReason,
+		mavenExecutable:  mavenExecutable,
+		mavenSource:      mavenSource,
+		mavenReason:      mavenReason,
+		args:             args,
+		rootBuildFile:    rootBuildFile,
+		rootCandidates:   rootCandidates,
+		moduleSelector:   resolveMavenModuleSelector(rootBuildFile, buildFile),
+		buildFile:        buildFile}
+}
+
+// Resolves whether mvnd should run the build, and why.
+// mvnd is skipped when disabled, not found, or any goal matches a mvndExclude pattern.
+// The daemon health is checked once Maven runs the build, see checkMvndStatus.
+func resolveMvndPolicy(config *Config, args *ParsedArgs, noMvnd error) (bool, string) {
+	if !config.maven.mvnd {
+		return false, ""
+	}
+
+	if noMvnd != nil {
+		return false, "mvnd = true but mvnd was not found"
+	}
+
+	// goals following a flag are parsed as tool args
+	rtargs, rargs := replaceMavenGoals(config, args)
+	for _, goal := range append(appendSafe(make([]string, 0), rtargs), rargs...) {
+		if strings.HasPrefix(goal, "-") {
+			continue
+		}
+		for _, pattern := range config.maven.mvndExclude {
+			if matched, _ := path.Match(pattern, goal); matched {
+				return false, "goal " + goal + " matches mvndExclude pattern " + pattern
+			}
+		}
+	}
+
+	return true, "mvnd = true"
+}
+
+// Falls back to Maven when mvnd --status reports a broken or incompatible daemon
+func (c *MavenCommand) checkMvndStatus() {
+	if !c.isMvnd() || len(c.mavenExecutable) == 0 {
+		return
+	}
+
+	if healthy, status := readMvndStatus(c.executable); !healthy {
+		c.executable = c.mavenExecutable
+		c.executableSource = c.mavenSource
+		c.executableReason = appendReason("mvnd --status reports "+status, c.mavenReason)
+	}
+}
+
+// Runs mvnd --status, returning false and the offending line if a daemon is broken or incompatible.
+// A mvnd that can not be run at all is left for the build to report.
+func readMvndStatus(mvnd string) (bool, string) {
+	output, err := exec.Command(mvnd, "--status").CombinedOutput()
+	var exerr *exec.ExitError
+	if err != nil && !errors.As(err, &exerr) {
+		return true, ""
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		l := strings.ToLower(line)
+		if strings.Contains(l, "broken") || strings.Contains(l, "incompatible") {
+			return false, strings.Join(strings.Fields(line), " ")
+		}
+	}
+
+	if exerr != nil {
+		return false, fmt.Sprintf("exit code %d", exerr.ExitCode())
+	}
+	return true, ""
+}
+
+func appendReason(reason string, other string) string {
+	if len(reason) == 0 {
+		return other
+	}
+	return reason + "; " + other
+}
+
+// Reads options from .mvn/maven.config, one or more per line
+func readMavenConfig(projectDir string) []string {
+	data, err := ioutil.ReadFile(filepath.Join(projectDir, ".mvn", "maven.config"))
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(data))
 }
 
 func resolveMavenRootDir(context Context,
@@ -218,67 +483,46 @@ func resolveMavenRootDir(context Context,
 
 func warnNoMavenWrapper(context Context, config *Config) {
 	if !config.general.quiet && context.IsExplicit() {
-		fmt.Printf("No %s set up for this project. ", resolveMavenWrapperExec(context))
-		fmt.Println()
-		fmt.Println("Please consider setting one up.")
-		fmt.Println("(https://maven.apache.org/)")
-		fmt.Println()
+		fmt.Fprintf(context.GetStdout(), "No %s set up for this project. ", resolveMavenWrapperExec(context))
+		fmt.Fprintln(context.GetStdout())
+		fmt.Fprintln(context.GetStdout(), "Please consider setting one up, e.g. with `gm -gw`.")
+		fmt.Fprintln(context.GetStdout(), "(https://maven.apache.org/)")
+		fmt.Fprintln(context.GetStdout())
 	}
 }
 
 func warnNoMaven(context Context, config *Config) {
 	if !config.general.quiet && context.IsExplicit() {
-		fmt.Printf("No %s found in path. Please install Maven.", resolveMavenExec(context))
-		fmt.Println()
-		fmt.Println("(https://maven.apache.org/download.cgi)")
-		fmt.Println()
+		fmt.Fprintf(context.GetStdout(), "No %s found in path. Please install Maven.", resolveMavenExec(context))
+		fmt.Fprintln(context.GetStdout())
+		fmt.Fprintln(context.GetStdout(), "(https://maven.apache.org/download.cgi)")
+		fmt.Fprintln(context.GetStdout())
 	}
 }
 
 // Finds the maven executable
-func findMavenExec(context Context) (string, error) {
-	maven := resolveMavenExec(context)
-	paths := context.GetPaths()
-
-	for i := range paths {
-		name := filepath.Join(paths[i], maven)
-		if context.FileExists(name) {
-			return filepath.Abs(name)
-		}
-	}
-
-	return "", errors.New(maven + " not found")
+func findMavenExec(context Context, config *Config) (string, string, error) {
+	return locateExecutable(context, "maven", "MAVEN_HOME", config.maven.executable, resolveMavenExec(context))
 }
 
 // Finds the mvnd executable
-func findMvndExec(context Context) (string, error) {
-	mvnd := resolveMvndExec(context)
-	paths := context.GetPaths()
-
-	for i := range paths {
-		name := filepath.Join(paths[i], mvnd)
-		if context.FileExists(name) {
-			return filepath.Abs(name)
-		}
-	}
-
-	return "", errors.New(mvnd + " not found")
+func findMvndExec(context Context) (string, string, error) {
+	return locateExecutable(context, "mvnd", "MVND_HOME", "", resolveMvndExec(context))
 }
 
 // Finds the Maven wrapper (if it exists)
 func findMavenWrapperExec(context Context, dir string) (string, error) {
 	wrapper := resolveMavenWrapperExec(context)
-	parentdir := filepath.Join(dir, "..")
-
-	if parentdir == dir {
-		return "", errors.New(wrapper + " not found")
-	}
-
 	path := filepath.Join(dir, wrapper)
 	if context.FileExists(path) {
 		return filepath.Abs(path)
 	}
 
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New(wrapper + " not found")
+	}
+
 	return findMavenWrapperExec(context, parentdir)
 }
 
@@ -300,34 +544,174 @@ func findExplicitMavenBuildFile(args *ParsedArgs) (bool, string) {
 
 // Finds the nearest pom.xml
 func findMavenBuildFile(context Context, dir string) (string, error) {
-	parentdir := filepath.Join(dir, "..")
-
-	if parentdir == dir {
-		return "", errors.New("Did not find pom.xml")
-	}
-
 	path := filepath.Join(dir, "pom.xml")
 	if context.FileExists(path) {
 		return filepath.Abs(path)
 	}
 
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New("Did not find pom.xml")
+	}
+
 	return findMavenBuildFile(context, parentdir)
 }
 
-// Finds the root pom.xml
-func findMavenRootFile(context Context, dir string) (string, error) {
-	parentdir := filepath.Join(dir, "..")
+// Finds the root pom.xml, that is, the outermost pom.xml that aggregates the module
+// defined by buildFile either directly or through intermediate aggregators.
+// The search does not go past the directory holding .mvn as Maven treats it as the
+// multi-module project directory.
+// Returns an explanation for every candidate that was accepted or rejected.
+func findMavenRootFile(context Context, buildFile string) (string, []string, error) {
+	candidates := make([]string, 0)
+	if len(buildFile) == 0 {
+		return "", candidates, errors.New("Did not find root pom.xml")
+	}
+
+	moduledir := filepath.Dir(buildFile)
+	projectdir, noProjectDir := findMavenProjectDir(context, moduledir)
+	rootBuildFile := ""
+
+	for dir := moduledir; ; {
+		if noProjectDir == nil && dir == projectdir {
+			candidates = append(candidates, "stop at "+projectdir+" (has .mvn directory)")
+			break
+		}
+
+		parentdir, ok := resolveSearchParent(context, dir)
+		if !ok {
+			break
+		}
+		dir = parentdir
+
+		path := filepath.Join(dir, "pom.xml")
+		if !context.FileExists(path) {
+			continue
+		}
 
-	if parentdir == dir {
-		return "", errors.New("Did not find root pom.xml")
+		if mavenPomAggregates(path, moduledir) {
+			candidates = append(candidates, "accept "+path+" (aggregates "+moduledir+")")
+			rootBuildFile = path
+			moduledir = dir
+		} else {
+			candidates = append(candidates, "reject "+path+" (does not list "+moduledir+" in <modules> nor <subprojects>)")
+		}
 	}
 
-	path := filepath.Join(dir, "pom.xml")
-	if context.FileExists(path) {
-		return filepath.Abs(path)
+	if len(rootBuildFile) == 0 {
+		return "", candidates, errors.New("Did not find root pom.xml")
 	}
+	return rootBuildFile, candidates, nil
+}
 
-	return findMavenRootFile(context, parentdir)
+// Finds the nearest directory holding a .mvn directory
+func findMavenProjectDir(context Context, dir string) (string, error) {
+	if isDirectory(filepath.Join(dir, ".mvn")) {
+		return filepath.Abs(dir)
+	}
+
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New("Did not find .mvn")
+	}
+
+	return findMavenProjectDir(context, parentdir)
+}
+
+type mavenPom struct {
+	Modules     []string `xml:"modules>module"`
+	Subprojects []string `xml:"subprojects>subproject"`
+	Profiles    []struct {
+		Modules     []string `xml:"modules>module"`
+		Subprojects []string `xml:"subprojects>subproject"`
+	} `xml:"profiles>profile"`
+}
+
+// Reads the modules (Maven 3) and subprojects (Maven 4) declared by the given pom.xml,
+// including those declared in profiles
+func readMavenModules(buildFile string) []string {
+	modules := make([]string, 0)
+
+	data, err := ioutil.ReadFile(buildFile)
+	if err != nil {
+		return modules
+	}
+
+	var pom mavenPom
+	if xml.Unmarshal(data, &pom) != nil {
+		return modules
+	}
+
+	modules = append(modules, pom.Modules...)
+	modules = append(modules, pom.Subprojects...)
+	for _, profile := range pom.Profiles {
+		modules = append(modules, profile.Modules...)
+		modules = append(modules, profile.Subprojects...)
+	}
+	return modules
+}
+
+// Resolves the pom file of a module declared by the pom.xml found at basedir.
+// A module may point to a directory or to a pom file.
+func resolveMavenModuleFile(basedir string, module string) string {
+	path := filepath.Join(basedir, filepath.FromSlash(strings.TrimSpace(module)))
+	if strings.HasSuffix(path, ".xml") {
+		return path
+	}
+	return filepath.Join(path, "pom.xml")
+}
+
+// Checks if the given pom.xml lists moduledir as one of its modules
+func mavenPomAggregates(buildFile string, moduledir string) bool {
+	basedir := filepath.Dir(buildFile)
+
+	for _, module := range readMavenModules(buildFile) {
+		if filepath.Dir(resolveMavenModuleFile(basedir, module)) == moduledir {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Resolves the -pl selector matching the module defined by buildFile.
+// Returns an empty string if buildFile is the root or it is not part of the reactor.
+func resolveMavenModuleSelector(rootBuildFile string, buildFile string) string {
+	if len(rootBuildFile) == 0 || len(buildFile) == 0 || rootBuildFile == buildFile {
+		return ""
+	}
+
+	moduledir := filepath.Dir(buildFile)
+	if !findMavenReactorModule(rootBuildFile, moduledir, make(map[string]bool)) {
+		return ""
+	}
+
+	rel, err := filepath.Rel(filepath.Dir(rootBuildFile), moduledir)
+	if err != nil {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// Walks the reactor modules of the given pom.xml looking for moduledir
+func findMavenReactorModule(buildFile string, moduledir string, visited map[string]bool) bool {
+	if visited[buildFile] {
+		return false
+	}
+	visited[buildFile] = true
+
+	basedir := filepath.Dir(buildFile)
+	for _, module := range readMavenModules(buildFile) {
+		moduleFile := resolveMavenModuleFile(basedir, module)
+		if filepath.Dir(moduleFile) == moduledir {
+			return true
+		}
+		if isRegularFile(moduleFile) && findMavenReactorModule(moduleFile, moduledir, visited) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // Resolves the mvnw executable (OS dependent)
@@ -353,3 +737,50 @@ func resolveMvndExec(context Context) string {
 	}
 	return "mvnd"
 }
+
+// Finds the minimum Java feature release required by the given pom files and
+// the .mvn/jvm.config of their project, returning it along with the file it was read from
+func findMavenRequiredJava(context Context, buildFiles ...string) (int, string) {
+	release := 0
+	source := ""
+
+	for _, buildFile := range buildFiles {
+		if len(buildFile) == 0 {
+			continue
+		}
+
+		r := findJavaRelease(buildFile, mavenJavaReleasePatterns, true)
+		if r > release {
+			release = r
+			source = buildFile
+		}
+
+		projectdir, noProjectDir := findMavenProjectDir(context, filepath.Dir(buildFile))
+		if noProjectDir == nil {
+			jvmConfig := filepath.Join(projectdir, ".mvn", "jvm.config")
+			if release < 9 && readMavenJvmConfigRequiresModules(jvmConfig) {
+				release = 9
+				source = jvmConfig
+			}
+		}
+	}
+
+	return release, source
+}
+
+// Checks if the given jvm.config uses options introduced with the module system (Java 9)
+func readMavenJvmConfigRequiresModules(jvmConfig string) bool {
+	data, err := ioutil.ReadFile(jvmConfig)
+	if err != nil {
+		return false
+	}
+
+	for _, option := range strings.Fields(string(data)) {
+		for _, moduleOption := range mavenJvmConfigModuleOptions {
+			if option == moduleOption || strings.HasPrefix(option, moduleOption+"=") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -18,6 +18,7 @@ package gum
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -412,3 +413,353 @@ func TestMvndSingle(t *testing.T) {
 		}
 	}
 }
+
+func TestMavenRootStopsAtMvnDirectory(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "mvn-dir", "project", "child"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindMaven(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"RootBuildFile", cmd.rootBuildFile, filepath.Join(pwd, "..", "pom.xml")},
+		{"BuildFile", cmd.buildFile, filepath.Join(pwd, "pom.xml")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestMavenRootSkipsUnrelatedParent(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "unrelated-parent", "sample"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindMaven(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"RootBuildFile", cmd.rootBuildFile, filepath.Join(pwd, "pom.xml")},
+		{"BuildFile", cmd.buildFile, filepath.Join(pwd, "pom.xml")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+
+	if len(cmd.rootCandidates) == 0 || !strings.HasPrefix(cmd.rootCandidates[0], "reject "+filepath.Join(pwd, "..", "pom.xml")) {
+		t.Errorf("rootCandidates: got %s, want rejection of the unrelated parent", cmd.rootCandidates)
+	}
+}
+
+func TestMavenRootWithNestedSubprojects(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "nested-modules"))
+	pwd := filepath.Join(root, "libs", "core")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindMaven(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"RootBuildFile", cmd.rootBuildFile, filepath.Join(root, "pom.xml")},
+		{"BuildFile", cmd.buildFile, filepath.Join(pwd, "pom.xml")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestMavenModuleScope(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "nested-modules"))
+	pwd := filepath.Join(root, "libs", "core")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "-go", "build"})
+	cmd := FindMaven(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	if cmd.moduleSelector != "libs/core" {
+		t.Errorf("moduleSelector: got %s, want libs/core", cmd.moduleSelector)
+	}
+
+	cmd.doConfigureMaven()
+	actual := strings.Join(cmd.args.Args, " ")
+	expected := "-f " + filepath.Join(root, "pom.xml") + " -pl libs/core -am verify"
+	if actual != expected {
+		t.Errorf("args: got %s, want %s", actual, expected)
+	}
+}
+
+func TestMavenKeepsUserProjectList(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "nested-modules"))
+	pwd := filepath.Join(root, "libs", "core")
+
+	var checks = []struct {
+		title    string
+		args     []string
+		affected []string
+	}{
+		{"ModuleScope", []string{"-gq", "-go", "install", "-pl", "foo"}, nil},
+		{"ModuleScopeToolArgs", []string{"-gq", "-go", "-pl", "foo", "install"}, nil},
+		{"Affected", []string{"-gq", "install", "-pl", "foo"}, []string{"libs/core"}},
+		{"AffectedToolArgs", []string{"-gq", "-pl", "foo", "install"}, []string{"libs/core"}},
+	}
+
+	for _, check := range checks {
+		t.Run(check.title, func(t *testing.T) {
+			context := testContext{
+				quiet:      true,
+				explicit:   true,
+				windows:    false,
+				workingDir: pwd,
+				paths:      []string{bin}}
+
+			// when:
+			args := ParseArgs(check.args)
+			cmd := FindMaven(context, &args)
+			if cmd == nil {
+				t.Fatal("Expected a command but got nil")
+			}
+			cmd.affectedModules = check.affected
+			cmd.doConfigureMaven()
+
+			// then:
+			actual := " " + strings.Join(cmd.args.Args, " ") + " "
+			if strings.Count(actual, " -pl ") != 1 || strings.Contains(actual, " -am ") || strings.Contains(actual, " -amd ") {
+				t.Errorf("args: got %s, want only -pl foo", actual)
+			}
+		})
+	}
+}
+
+func TestMavenExpectedVersionFromSdkmanrc(t *testing.T) {
+	// given:
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "sdkmanrc"))
+	bin := filepath.Join(pwd, "bin")
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "verify"})
+	cmd := FindMaven(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	cmd.config.maven.mismatch = "fail"
+	cmd.doConfigureMaven()
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"ExpectedVersion", cmd.version.expected, "3.9.9"},
+		{"ExpectedVersionSource", cmd.version.source, filepath.Join(pwd, ".sdkmanrc")},
+		{"DetectedVersion", cmd.version.detected, "3.9.9"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestMavenExpectedVersionFromConfig(t *testing.T) {
+	// given:
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "single-with-wrapper"))
+	context := testContext{workingDir: pwd}
+	config := newConfig()
+	config.maven.version = "4.0.0"
+
+	// when:
+	version := findExpectedToolVersion(context, pwd, filepath.Join(".mvn", "wrapper", "maven-wrapper.properties"), mavenDistributionVersionPattern, "maven", config.maven.version)
+
+	// then:
+	if version.String() != "4.0.0 ([maven] version)" {
+		t.Errorf("version: got %s, want 4.0.0 ([maven] version)", version)
+	}
+}
+
+func TestMvndPolicy(t *testing.T) {
+	// given:
+	healthy, _ := filepath.Abs(filepath.Join("..", "tests", "mvnd", "healthy"))
+	broken, _ := filepath.Abs(filepath.Join("..", "tests", "mvnd", "broken"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "mvnd", "policy"))
+
+	var checks = []struct {
+		title, bin, goal, executable, reason string
+	}{
+		{"Healthy", healthy, "verify", filepath.Join(healthy, "mvnd"), "mvnd = true"},
+		{"Excluded", healthy, "release:prepare", filepath.Join(pwd, "mvnw"), "goal release:prepare matches mvndExclude pattern release:*; project wrapper found"},
+		{"ExcludedExact", healthy, "site", filepath.Join(pwd, "mvnw"), "goal site matches mvndExclude pattern site; project wrapper found"},
+		{"ExcludedAfterFlag", healthy, "-B release:prepare", filepath.Join(pwd, "mvnw"), "goal release:prepare matches mvndExclude pattern release:*; project wrapper found"},
+		{"ExcludedAfterProperty", healthy, "-DskipTests site", filepath.Join(pwd, "mvnw"), "goal site matches mvndExclude pattern site; project wrapper found"},
+		{"Broken", broken, "verify", filepath.Join(pwd, "mvnw"), "mvnd --status reports 4ac13d4b 2331 inet:/127.0.0.1:40215 Broken 512m 2026-10-17T10:12:31 /usr/lib/jvm/java-21; project wrapper found"},
+	}
+
+	for _, check := range checks {
+		context := testContext{
+			quiet:      true,
+			explicit:   true,
+			windows:    false,
+			workingDir: pwd,
+			paths:      []string{check.bin}}
+
+		// when:
+		args := ParseArgs(append([]string{"-gq"}, strings.Fields(check.goal)...))
+		cmd := FindMaven(context, &args)
+
+		// then:
+		if cmd == nil {
+			t.Errorf("%s: expected a command but got nil", check.title)
+			continue
+		}
+		cmd.checkMvndStatus()
+		if cmd.executable != check.executable {
+			t.Errorf("%s: executable got %s, want %s", check.title, cmd.executable, check.executable)
+		}
+		if cmd.executableReason != check.reason {
+			t.Errorf("%s: reason got %s, want %s", check.title, cmd.executableReason, check.reason)
+		}
+	}
+}
+
+func TestFindMavenDefersMvndStatus(t *testing.T) {
+	// given:
+	broken, _ := filepath.Abs(filepath.Join("..", "tests", "mvnd", "broken"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "mvnd", "policy"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{broken}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "verify"})
+	cmd := FindMaven(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+	if cmd.executable != filepath.Join(broken, "mvnd") {
+		t.Errorf("executable: got %s, want %s", cmd.executable, filepath.Join(broken, "mvnd"))
+	}
+	if cmd.mavenExecutable != filepath.Join(pwd, "mvnw") {
+		t.Errorf("maven executable: got %s, want %s", cmd.mavenExecutable, filepath.Join(pwd, "mvnw"))
+	}
+}
+
+func TestMvndPassesMavenConfig(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "mvnd", "healthy"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "mvnd", "policy"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "verify"})
+	cmd := FindMaven(context, &args)
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+	cmd.doConfigureMaven()
+
+	// then:
+	actual := strings.Join(cmd.args.Args, " ")
+	expected := "-T 1C --fail-at-end -f " + filepath.Join(pwd, "pom.xml") + " verify"
+	if actual != expected {
+		t.Errorf("args: got %s, want %s", actual, expected)
+	}
+}
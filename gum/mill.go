@@ -0,0 +1,342 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// MillCommand defines an executable Mill command
+type MillCommand struct {
+	context          Context
+	config           *Config
+	executable       string
+	args             *ParsedArgs
+	rootDir          string
+	buildFile        string
+	rootBuildFile    string
+	executableSource string
+}
+
+// Execute executes the given command
+func (c MillCommand) Execute() int {
+	if !trustProject(c.context, c.config, c.wrappers()...) {
+		c.context.Exit(-1)
+	}
+	c.doConfigureMill()
+	return c.doExecuteMill()
+}
+
+// Project local wrappers that must be trusted before execution
+func (c *MillCommand) wrappers() []string {
+	if c.executableSource == "wrapper" {
+		return []string{c.executable}
+	}
+	return nil
+}
+
+func (c *MillCommand) doConfigureMill() {
+	c.context.CheckIsExecutable(c.executable)
+
+	args := make([]string, 0)
+
+	banner := make([]string, 0)
+	banner = append(banner, "Using mill at '"+c.executable+"'")
+	banner = append(banner, "to run buildFile '"+c.rootBuildFile+"':")
+	debug := c.args.HasGumFlag("gd")
+	skipReplace := c.args.HasGumFlag("gr")
+
+	if debug {
+		c.config.setDebug(debug)
+	}
+	if skipReplace {
+		c.config.mill.setReplace(!skipReplace)
+	}
+	c.debugConfig()
+	otargs := c.args.Tool
+	oargs := c.args.Args
+	rtargs, rargs := replaceMillTasks(c.config, c.args)
+
+	args = appendSafe(args, rtargs)
+	c.args.Args = appendSafe(args, rargs)
+
+	c.debugMill(otargs, oargs, rtargs, rargs)
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(c.context.GetStdout(), strings.Join(banner, " "))
+	}
+}
+
+func (c *MillCommand) doExecuteMill() int {
+	cmd := exec.Command(c.executable, c.args.Args...)
+	cmd.Dir = c.rootDir
+	cmd.Stdout = c.context.GetStdout()
+	cmd.Stderr = c.context.GetStderr()
+	err := cmd.Run()
+	var exerr *exec.ExitError
+	if errors.As(err, &exerr) {
+		return exerr.ExitCode()
+	}
+	if err != nil {
+		fmt.Fprintln(c.context.GetStderr(), err)
+		return -1
+	}
+	return 0
+}
+
+func (c *MillCommand) debugConfig() {
+	if c.args.HasGumFlag("gc") {
+		c.config.print(c.context.GetStdout())
+		c.context.Exit(0)
+	}
+}
+
+func (c *MillCommand) debugMill(otargs []string, oargs []string, rtargs []string, rargs []string) {
+	if c.config.general.debug {
+		fmt.Fprintln(c.context.GetStdout(), "replace            = ", c.config.mill.replace)
+		fmt.Fprintln(c.context.GetStdout(), "pwd                = ", c.context.GetWorkingDir())
+		fmt.Fprintln(c.context.GetStdout(), "rootDir            = ", c.rootDir)
+		fmt.Fprintln(c.context.GetStdout(), "rootBuildFile      = ", c.rootBuildFile)
+		fmt.Fprintln(c.context.GetStdout(), "buildFile          = ", c.buildFile)
+		fmt.Fprintln(c.context.GetStdout(), "original tool args = ", otargs)
+		if c.config.mill.replace {
+			fmt.Fprintln(c.context.GetStdout(), "replaced tool args = ", rtargs)
+		}
+		fmt.Fprintln(c.context.GetStdout(), "original args      = ", oargs)
+		if c.config.mill.replace {
+			fmt.Fprintln(c.context.GetStdout(), "replaced args      = ", rargs)
+		}
+		fmt.Fprintln(c.context.GetStdout(), "actual args        = ", c.args.Args)
+		fmt.Fprintln(c.context.GetStdout(), "")
+	}
+}
+
+func replaceMillTasks(config *Config, args *ParsedArgs) ([]string, []string) {
+	if config.mill.replace {
+		return replaceArgs(args.Tool, config.mill.mappings, false), replaceArgs(args.Args, config.mill.mappings, false)
+	}
+
+	return args.Tool, args.Args
+}
+
+// FindMill finds and executes mill/millw
+func FindMill(context Context, args *ParsedArgs) *MillCommand {
+	pwd := context.GetWorkingDir()
+
+	buildFile, noBuildFile := findMillBuildFile(context, pwd)
+	rootBuildFile, noRootBuildFile := findMillRootFile(context, pwd, "", errors.New("Did not find root build file"))
+	if noRootBuildFile != nil {
+		rootBuildFile = buildFile
+	}
+	rootdir := resolveMillRootDir(context, pwd, rootBuildFile)
+	if filepath.Dir(rootBuildFile) != rootdir {
+		rootBuildFile, _ = findMillBuildFile(context, rootdir)
+	}
+	config := ReadConfig(context, rootdir)
+	quiet := args.HasGumFlag("gq")
+
+	if quiet {
+		config.setQuiet(quiet)
+	}
+
+	millw, noWrapper := findMillWrapperExec(context, rootdir)
+	mill, noMill := findMillExec(context)
+
+	var executable string
+	var executableSource string
+	if noWrapper == nil {
+		executable = millw
+		executableSource = "wrapper"
+	} else if noMill == nil {
+		warnNoMillWrapper(context, config)
+		executable = mill
+	} else {
+		warnNoMill(context, config)
+
+		if context.IsExplicit() {
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	if noBuildFile != nil {
+		if context.IsExplicit() {
+			fmt.Fprintln(context.GetStdout(), "No Mill project found")
+			fmt.Fprintln(context.GetStdout())
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	return &MillCommand{
+		context:          context,
+		config:           config,
+		executable:       executable,
+		args:             args,
+		rootDir:          rootdir,
+		buildFile:        buildFile,
+		rootBuildFile:    rootBuildFile,
+		executableSource: executableSource}
+}
+
+func resolveMillRootDir(context Context,
+	pwd string,
+	rootBuildFile string) string {
+
+	// .mill-version pins the root of nested builds
+	versionFile, noVersionFile := findMillVersionFile(context, pwd)
+	if noVersionFile == nil {
+		versiondir := filepath.Dir(versionFile)
+		if context.FileExists(filepath.Join(versiondir, "build.mill")) ||
+			context.FileExists(filepath.Join(versiondir, "build.sc")) {
+			return versiondir
+		}
+	}
+	return filepath.Dir(rootBuildFile)
+}
+
+func warnNoMillWrapper(context Context, config *Config) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(context.GetStdout(), "No %s set up for this project. ", resolveMillWrapperExec(context))
+		fmt.Fprintln(context.GetStdout())
+		fmt.Fprintln(context.GetStdout(), "Please consider setting one up.")
+		fmt.Fprintln(context.GetStdout(), "(https://mill-build.org/mill/cli/installation-ide.html)")
+		fmt.Fprintln(context.GetStdout())
+	}
+}
+
+func warnNoMill(context Context, config *Config) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(context.GetStdout(), "No %s found in path. Please install Mill.", resolveMillExec(context))
+		fmt.Fprintln(context.GetStdout())
+		fmt.Fprintln(context.GetStdout(), "(https://mill-build.org/mill/cli/installation-ide.html)")
+		fmt.Fprintln(context.GetStdout())
+	}
+}
+
+// Finds the mill executable
+func findMillExec(context Context) (string, error) {
+	mill := resolveMillExec(context)
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], mill)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New(mill + " not found")
+}
+
+// Finds the project local mill/millw wrapper (if it exists)
+func findMillWrapperExec(context Context, dir string) (string, error) {
+	var wrappers [2]string
+	wrappers[0] = resolveMillExec(context)
+	wrappers[1] = resolveMillWrapperExec(context)
+
+	for i := range wrappers {
+		path := filepath.Join(dir, wrappers[i])
+		if isRegularFile(path) {
+			return filepath.Abs(path)
+		}
+	}
+
+	return "", errors.New(wrappers[1] + " not found")
+}
+
+// Finds the nearest Mill build file
+// Checks the following paths in order:
+// - build.mill
+// - build.sc
+func findMillBuildFile(context Context, dir string) (string, error) {
+	var buildFiles [2]string
+	buildFiles[0] = "build.mill"
+	buildFiles[1] = "build.sc"
+
+	for i := range buildFiles {
+		path := filepath.Join(dir, buildFiles[i])
+		if context.FileExists(path) {
+			return filepath.Abs(path)
+		}
+	}
+
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New("Did not find Mill build file")
+	}
+
+	return findMillBuildFile(context, parentdir)
+}
+
+// Finds the outermost Mill build file
+func findMillRootFile(context Context, dir string, found string, notFound error) (string, error) {
+	var buildFiles [2]string
+	buildFiles[0] = "build.mill"
+	buildFiles[1] = "build.sc"
+
+	for i := range buildFiles {
+		path := filepath.Join(dir, buildFiles[i])
+		if context.FileExists(path) {
+			found, _ = filepath.Abs(path)
+			notFound = nil
+			break
+		}
+	}
+
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return found, notFound
+	}
+
+	return findMillRootFile(context, parentdir, found, notFound)
+}
+
+// Finds the nearest .mill-version file
+func findMillVersionFile(context Context, dir string) (string, error) {
+	path := filepath.Join(dir, ".mill-version")
+	if context.FileExists(path) {
+		return filepath.Abs(path)
+	}
+
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New("Did not find .mill-version")
+	}
+
+	return findMillVersionFile(context, parentdir)
+}
+
+// Resolves the millw executable (OS dependent)
+func resolveMillWrapperExec(context Context) string {
+	if context.IsWindows() {
+		return "millw.bat"
+	}
+	return "millw"
+}
+
+// Resolves the mill executable (OS dependent)
+func resolveMillExec(context Context) string {
+	if context.IsWindows() {
+		return "mill.bat"
+	}
+	return "mill"
+}
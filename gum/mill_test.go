@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMillSingleWithWrapper(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "mill", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "mill", "single-with-wrapper"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "compile", "test"})
+	cmd := FindMill(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(pwd, "millw")},
+		{"RootDir", cmd.rootDir, pwd},
+		{"RootBuildFile", cmd.rootBuildFile, filepath.Join(pwd, "build.mill")},
+		{"BuildFile", cmd.buildFile, filepath.Join(pwd, "build.mill")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+
+	cmd.doConfigureMill()
+	if len(cmd.args.Args) != 2 || cmd.args.Args[0] != "__.compile" || cmd.args.Args[1] != "__.test" {
+		t.Errorf("args: got %s, want [__.compile __.test]", cmd.args.Args)
+	}
+}
+
+func TestMillSingleWithoutWrapper(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "mill", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "mill", "single-without-wrapper"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindMill(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "mill")},
+		{"RootDir", cmd.rootDir, pwd},
+		{"RootBuildFile", cmd.rootBuildFile, filepath.Join(pwd, "build.mill")},
+		{"BuildFile", cmd.buildFile, filepath.Join(pwd, "build.mill")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestMillParent(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "mill", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "mill", "parent", "child"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindMill(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "mill")},
+		{"RootDir", cmd.rootDir, filepath.Join(pwd, "..")},
+		{"RootBuildFile", cmd.rootBuildFile, filepath.Join(pwd, "..", "build.mill")},
+		{"BuildFile", cmd.buildFile, filepath.Join(pwd, "build.mill")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestMillLegacyBuildFile(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "mill", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "mill", "legacy"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindMill(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	if cmd.buildFile != filepath.Join(pwd, "build.sc") {
+		t.Errorf("BuildFile: got %s, want %s", cmd.buildFile, filepath.Join(pwd, "build.sc"))
+	}
+}
+
+func TestMillWithoutExecutables(t *testing.T) {
+	// given:
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "mill", "single-without-wrapper"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindMill(context, &args)
+
+	// then:
+	if cmd != nil {
+		t.Error("Expected a nil command but got something")
+	}
+}
@@ -0,0 +1,343 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// SbtCommand defines an executable sbt command
+type SbtCommand struct {
+	context          Context
+	config           *Config
+	rootdir          string
+	executable       string
+	args             *ParsedArgs
+	buildFile        string
+	executableSource string
+}
+
+// Execute executes the given command
+func (c SbtCommand) Execute() int {
+	if !trustProject(c.context, c.config, c.wrappers()...) {
+		c.context.Exit(-1)
+	}
+	c.doConfigureSbt()
+	return c.doExecuteSbt()
+}
+
+// Project local wrappers that must be trusted before execution
+func (c *SbtCommand) wrappers() []string {
+	if c.executableSource == "wrapper" {
+		return []string{c.executable}
+	}
+	return nil
+}
+
+func (c *SbtCommand) doConfigureSbt() {
+	c.context.CheckIsExecutable(c.executable)
+
+	args := make([]string, 0)
+
+	banner := make([]string, 0)
+	banner = append(banner, "Using sbt at '"+c.executable+"'")
+	banner = append(banner, "to run project at '"+c.rootdir+"':")
+	debug := c.args.HasGumFlag("gd")
+	skipReplace := c.args.HasGumFlag("gr")
+
+	if debug {
+		c.config.setDebug(debug)
+	}
+	if skipReplace {
+		c.config.sbt.setReplace(!skipReplace)
+	}
+	c.debugConfig()
+	otargs := c.args.Tool
+	oargs := c.args.Args
+	rtargs, rargs := replaceSbtCommands(c.config, c.args)
+
+	args = appendSafe(args, rtargs)
+	c.args.Args = appendSafe(args, rargs)
+
+	c.debugSbt(otargs, oargs, rtargs, rargs)
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(c.context.GetStdout(), strings.Join(banner, " "))
+	}
+}
+
+func (c *SbtCommand) doExecuteSbt() int {
+	cmd := exec.Command(c.executable, c.args.Args...)
+	cmd.Dir = c.rootdir
+	cmd.Stdout = c.context.GetStdout()
+	cmd.Stderr = c.context.GetStderr()
+	cmd.Stdin = os.Stdin
+	err := cmd.Run()
+	var exerr *exec.ExitError
+	if errors.As(err, &exerr) {
+		return exerr.ExitCode()
+	}
+	if err != nil {
+		fmt.Fprintln(c.context.GetStderr(), err)
+		return -1
+	}
+	return 0
+}
+
+func (c *SbtCommand) debugConfig() {
+	if c.args.HasGumFlag("gc") {
+		c.config.print(c.context.GetStdout())
+		c.context.Exit(0)
+	}
+}
+
+func (c *SbtCommand) debugSbt(otargs []string, oargs []string, rtargs []string, rargs []string) {
+	if c.config.general.debug {
+		fmt.Fprintln(c.context.GetStdout(), "replace            = ", c.config.sbt.replace)
+		fmt.Fprintln(c.context.GetStdout(), "pwd                = ", c.context.GetWorkingDir())
+		fmt.Fprintln(c.context.GetStdout(), "rootdir            = ", c.rootdir)
+		fmt.Fprintln(c.context.GetStdout(), "executable         = ", c.executable)
+		fmt.Fprintln(c.context.GetStdout(), "buildFile          = ", c.buildFile)
+		fmt.Fprintln(c.context.GetStdout(), "original tool args = ", otargs)
+		if c.config.sbt.replace {
+			fmt.Fprintln(c.context.GetStdout(), "replaced tool args = ", rtargs)
+		}
+		fmt.Fprintln(c.context.GetStdout(), "original args      = ", oargs)
+		if c.config.sbt.replace {
+			fmt.Fprintln(c.context.GetStdout(), "replaced args      = ", rargs)
+		}
+		fmt.Fprintln(c.context.GetStdout(), "actual args        = ", c.args.Args)
+		fmt.Fprintln(c.context.GetStdout(), "")
+	}
+}
+
+func replaceSbtCommands(config *Config, args *ParsedArgs) ([]string, []string) {
+	if config.sbt.replace {
+		return replaceArgs(args.Tool, config.sbt.mappings, false), replaceArgs(args.Args, config.sbt.mappings, false)
+	}
+
+	return args.Tool, args.Args
+}
+
+// FindSbt finds and executes sbt
+func FindSbt(context Context, args *ParsedArgs) *SbtCommand {
+	pwd := context.GetWorkingDir()
+
+	buildFile, noBuildFile := findSbtBuildFile(context, pwd)
+	rootFile, noRootFile := findSbtRootFile(context, pwd)
+	rootdir := resolveSbtRootDir(context, buildFile, rootFile)
+	config := ReadConfig(context, rootdir)
+	quiet := args.HasGumFlag("gq")
+
+	if quiet {
+		config.setQuiet(quiet)
+	}
+
+	sbtw, noWrapper := findSbtWrapperExec(context, rootdir)
+	sbtn, noSbtn := findSbtnExec(context)
+	sbtx, noSbtx := findSbtxExec(context)
+	sbt, noSbt := findSbtExec(context)
+
+	var executable string
+	var executableSource string
+	if noWrapper == nil {
+		executable = sbtw
+		executableSource = "wrapper"
+	} else if noSbtn == nil {
+		executable = sbtn
+	} else if noSbtx == nil {
+		executable = sbtx
+	} else if noSbt == nil {
+		executable = sbt
+	} else {
+		warnNoSbt(context, config)
+
+		if context.IsExplicit() {
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	if noBuildFile != nil && noRootFile != nil {
+		if context.IsExplicit() {
+			fmt.Fprintln(context.GetStdout(), "No sbt project found")
+			fmt.Fprintln(context.GetStdout())
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	if noBuildFile != nil {
+		buildFile = rootFile
+	}
+
+	return &SbtCommand{
+		context:          context,
+		config:           config,
+		rootdir:          rootdir,
+		executable:       executable,
+		args:             args,
+		buildFile:        buildFile,
+		executableSource: executableSource}
+}
+
+func resolveSbtRootDir(context Context,
+	buildFile string,
+	rootFile string) string {
+
+	if context.FileExists(rootFile) {
+		// rootFile points to project/build.properties
+		return filepath.Dir(filepath.Dir(rootFile))
+	}
+	return filepath.Dir(buildFile)
+}
+
+func warnNoSbt(context Context, config *Config) {
+	if !config.general.quiet && context.IsExplicit() {
+		fmt.Fprintf(context.GetStdout(), "No %s found in path. Please install sbt.", resolveSbtExec(context))
+		fmt.Fprintln(context.GetStdout())
+		fmt.Fprintln(context.GetStdout(), "(https://www.scala-sbt.org/download/)")
+		fmt.Fprintln(context.GetStdout())
+	}
+}
+
+// Finds the sbt executable
+func findSbtExec(context Context) (string, error) {
+	sbt := resolveSbtExec(context)
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], sbt)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New(sbt + " not found")
+}
+
+// Finds the sbtn executable (native thin client)
+func findSbtnExec(context Context) (string, error) {
+	sbtn := resolveSbtnExec(context)
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], sbtn)
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New(sbtn + " not found")
+}
+
+// Finds the sbtx executable (sbt-extras launcher)
+func findSbtxExec(context Context) (string, error) {
+	if context.IsWindows() {
+		return "", errors.New("sbtx not supported")
+	}
+
+	paths := context.GetPaths()
+
+	for i := range paths {
+		name := filepath.Join(paths[i], "sbtx")
+		if context.FileExists(name) {
+			return filepath.Abs(name)
+		}
+	}
+
+	return "", errors.New("sbtx not found")
+}
+
+// Finds a project local sbt launcher script (if it exists)
+func findSbtWrapperExec(context Context, dir string) (string, error) {
+	wrapper := resolveSbtExec(context)
+
+	path := filepath.Join(dir, wrapper)
+	if isRegularFile(path) {
+		return filepath.Abs(path)
+	}
+
+	if !context.IsWindows() {
+		path = filepath.Join(dir, "sbtx")
+		if isRegularFile(path) {
+			return filepath.Abs(path)
+		}
+	}
+
+	return "", errors.New(wrapper + " not found")
+}
+
+// Finds the nearest sbt build file
+// Checks the following paths in order:
+// - build.sbt
+// - project/build.properties
+func findSbtBuildFile(context Context, dir string) (string, error) {
+	var buildFiles [2]string
+	buildFiles[0] = "build.sbt"
+	buildFiles[1] = filepath.Join("project", "build.properties")
+
+	for i := range buildFiles {
+		path := filepath.Join(dir, buildFiles[i])
+		if context.FileExists(path) {
+			return filepath.Abs(path)
+		}
+	}
+
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New("Did not find build.sbt")
+	}
+
+	return findSbtBuildFile(context, parentdir)
+}
+
+// Finds the project/build.properties that marks the root of the build
+func findSbtRootFile(context Context, dir string) (string, error) {
+	path := filepath.Join(dir, "project", "build.properties")
+	if context.FileExists(path) {
+		return filepath.Abs(path)
+	}
+
+	parentdir, ok := resolveSearchParent(context, dir)
+	if !ok {
+		return "", errors.New("Did not find project/build.properties")
+	}
+
+	return findSbtRootFile(context, parentdir)
+}
+
+// Resolves the sbt executable (OS dependent)
+func resolveSbtExec(context Context) string {
+	if context.IsWindows() {
+		return "sbt.bat"
+	}
+	return "sbt"
+}
+
+// Resolves the sbtn executable (OS dependent)
+func resolveSbtnExec(context Context) string {
+	if context.IsWindows() {
+		return "sbtn.exe"
+	}
+	return "sbtn"
+}
@@ -0,0 +1,470 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// markers of a project root, per tool in discovery order
+var scanMarkers = [][2]string{
+	{"gradle", "settings.gradle"},
+	{"gradle", "settings.gradle.kts"},
+	{"gradle", "build.gradle"},
+	{"gradle", "build.gradle.kts"},
+	{"maven", "pom.xml"},
+	{"ant", "build.xml"},
+	{"amper", "project.yaml"},
+	{"amper", "module.yaml"},
+	{"sbt", "build.sbt"},
+	{"mill", "build.mill"},
+	{"mill", "build.sc"},
+	{"bazel", "MODULE.bazel"},
+	{"bazel", "REPO.bazel"},
+	{"bazel", "WORKSPACE.bazel"},
+	{"bazel", "WORKSPACE"},
+	{"clojure", "project.clj"},
+	{"clojure", "deps.edn"},
+	{"bach", ".bach"}}
+
+// ScannedProject describes a project root found below the working directory.
+// Paths inside the scanned tree are relative to the working directory.
+type ScannedProject struct {
+	Dir          string                 `json:"dir"`
+	Tool         string                 `json:"tool"`
+	BuildFile    string                 `json:"buildFile,omitempty"`
+	SettingsFile string                 `json:"settingsFile,omitempty"`
+	Executable   string                 `json:"executable,omitempty"`
+	Wrapper      string                 `json:"wrapper,omitempty"`
+	ConfigFile   string                 `json:"configFile,omitempty"`
+	Config       map[string]interface{} `json:"config"`
+	// the context and the tool resolved at the project directory, nil if the tool was not found
+	context   Context
+	candidate *toolCandidate
+}
+
+// a .gitignore pattern, relative to the directory holding the file
+type ignorePattern struct {
+	dir      string
+	pattern  string
+	negate   bool
+	anchored bool
+	dirOnly  bool
+}
+
+// scanContext resolves tools as if gm had been invoked at the given directory
+type scanContext struct {
+	Context
+	workingDir string
+}
+
+func (c scanContext) IsExplicit() bool {
+	return false
+}
+
+func (c scanContext) GetWorkingDir() string {
+	return c.workingDir
+}
+
+func (c scanContext) Exit(code int) {
+	panic(projectExit{code: code})
+}
+
+// projectExit is raised by the Exit of a project context to stop resolving or running
+// a single project instead of gm
+type projectExit struct {
+	code int
+}
+
+// ListProjects prints every project found below the working directory as a table, or as JSON with --json
+func ListProjects(context Context, args *ParsedArgs) {
+	config := ReadConfig(context, context.GetWorkingDir())
+	projects := ScanProjects(context, config)
+
+	for _, arg := range args.Tool {
+		if arg == "--json" {
+			data, err := json.MarshalIndent(projects, "", "  ")
+			if err != nil {
+				fmt.Fprintln(context.GetStdout(), err)
+				context.Exit(-1)
+				return
+			}
+			fmt.Fprintln(context.GetStdout(), string(data))
+			return
+		}
+	}
+
+	if len(projects) == 0 {
+		fmt.Fprintln(context.GetStdout(), "Did not find any projects")
+		return
+	}
+
+	rows := make([][]string, 0)
+	for _, p := range projects {
+		rows = append(rows, []string{
+			p.Dir,
+			p.Tool,
+			orDash(p.BuildFile, p.SettingsFile),
+			orDash(p.Wrapper),
+			orDash(p.ConfigFile),
+			formatScannedConfig(p.Config)})
+	}
+	config.theme.t.PrintTable(context.GetStdout(), []string{"PROJECT", "TOOL", "BUILD FILE", "WRAPPER", "CONFIG FILE", "CONFIG"}, rows)
+}
+
+// ScanProjects walks the tree below the working directory and resolves every project root.
+// Directories ignored by .gitignore or matching [general] scanExclude are skipped, so are the
+// modules declared by a Maven or Gradle project already found and the directories below any
+// other project.
+func ScanProjects(context Context, config *Config) []ScannedProject {
+	args := &ParsedArgs{
+		Gum:  map[string]struct{}{"gq": {}},
+		Tool: make([]string, 0),
+		Args: make([]string, 0)}
+
+	return scanProjects(context, config, args, func(dir string) Context {
+		return scanContext{Context: context, workingDir: dir}
+	})
+}
+
+// Resolves every project root with the given arguments, each tool at its own context
+func scanProjects(context Context, config *Config, args *ParsedArgs, contextFor func(dir string) Context) []ScannedProject {
+	pwd := context.GetWorkingDir()
+	projects := make([]ScannedProject, 0)
+	roots := make(map[string][]string)
+	ignores := make([]ignorePattern, 0)
+
+	filepath.WalkDir(pwd, func(dir string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return nil
+		}
+		if dir != pwd {
+			if entry.Name() == ".git" || entry.Name() == ".hg" || entry.Name() == ".svn" ||
+				isIgnored(ignores, dir, true) || isScanExcluded(config, pwd, dir) {
+				return filepath.SkipDir
+			}
+		}
+		ignores = append(ignores, readIgnorePatterns(dir)...)
+
+		for _, tool := range findScanMarkers(context, dir) {
+			if isNestedProject(roots[tool[0]], dir, tool[0]) {
+				continue
+			}
+			roots[tool[0]] = append(roots[tool[0]], dir)
+			projects = append(projects, scanProject(context, contextFor(dir), args.copy(), pwd, dir, tool[0], tool[1]))
+		}
+		return nil
+	})
+
+	return projects
+}
+
+// Resolves the tool found at the given directory as gm would when invoked there
+func scanProject(context Context, pcontext Context, args *ParsedArgs, pwd string, dir string, tool string, marker string) ScannedProject {
+	project := ScannedProject{
+		Dir:       relativeScanPath(pwd, dir),
+		Tool:      tool,
+		BuildFile: relativeScanPath(pwd, filepath.Join(dir, marker)),
+		context:   pcontext}
+
+	candidate, config := findScanCandidate(pcontext, args, tool, dir)
+	if candidate != nil {
+		project.candidate = candidate
+		config = candidate.config
+		if tool != "bach" {
+			project.BuildFile = relativeScanPath(pwd, candidate.buildFile)
+		}
+		project.SettingsFile = relativeScanPath(pwd, candidate.settingsFile)
+		project.Executable = relativeScanPath(pwd, candidate.executable)
+		if isWrapperExecutable(candidate.executable, dir) {
+			project.Wrapper = project.Executable
+		}
+	}
+
+	project.ConfigFile = relativeScanPath(pwd, config.projectFile)
+	project.Config = resolveScannedConfig(config, tool)
+	if tool == "bach" {
+		project.Config["version"], _ = findBachVersion(context, config, dir)
+	}
+	return project
+}
+
+// Finds the given tool and its config. A project that exits while being resolved, e.g. due
+// to an invalid config, is reported as not found with the default settings.
+func findScanCandidate(context Context, args *ParsedArgs, tool string, dir string) (candidate *toolCandidate, config *Config) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(projectExit); !ok {
+				panic(r)
+			}
+			candidate, config = nil, newConfig()
+		}
+	}()
+
+	if candidate = findToolCandidate(context, args, tool); candidate != nil {
+		return candidate, candidate.config
+	}
+	return nil, ReadConfig(context, dir)
+}
+
+// Finds the tools whose markers are found at the given directory, once per tool
+func findScanMarkers(context Context, dir string) [][2]string {
+	markers := make([][2]string, 0)
+	for _, marker := range scanMarkers {
+		if len(markers) > 0 && markers[len(markers)-1][0] == marker[0] {
+			continue
+		}
+		if context.FileExists(filepath.Join(dir, marker[1])) {
+			markers = append(markers, marker)
+		}
+	}
+	return markers
+}
+
+// Checks whether the given directory belongs to one of the given roots of the same tool.
+// Maven modules and Gradle projects belong to a root only when its pom.xml lists them in
+// <modules> or its settings file includes them, other tools own every directory below a root.
+func isNestedProject(roots []string, dir string, tool string) bool {
+	for _, root := range roots {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+
+		switch tool {
+		case "maven":
+			if findMavenReactorModule(filepath.Join(root, "pom.xml"), dir, make(map[string]bool)) {
+				return true
+			}
+		case "gradle":
+			if isGradleProjectOf(root, dir) {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// Checks whether the settings file at root includes the given directory, buildSrc is always part of the build
+func isGradleProjectOf(root string, dir string) bool {
+	if dir == filepath.Join(root, "buildSrc") {
+		return true
+	}
+	for _, settings := range []string{"settings.gradle", "settings.gradle.kts"} {
+		for _, projectdir := range readGradleProjects(filepath.Join(root, settings)) {
+			if filepath.Clean(projectdir) == dir {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Wrappers live at the project directory or at one of its parents
+func isWrapperExecutable(executable string, dir string) bool {
+	if len(executable) == 0 || !filepath.IsAbs(executable) {
+		return false
+	}
+	rel, err := filepath.Rel(filepath.Dir(executable), dir)
+	return err == nil && !strings.HasPrefix(rel, "..")
+}
+
+func relativeScanPath(pwd string, file string) string {
+	if len(file) == 0 {
+		return file
+	}
+	rel, err := filepath.Rel(pwd, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return file
+	}
+	return filepath.ToSlash(rel)
+}
+
+// Matches the path relative to the working directory as well as the directory name
+func isScanExcluded(config *Config, pwd string, dir string) bool {
+	rel := relativeScanPath(pwd, dir)
+	for _, exclude := range config.general.scanExclude {
+		exclude = strings.TrimSuffix(strings.TrimSpace(exclude), "/")
+		if m, _ := path.Match(exclude, rel); m {
+			return true
+		}
+		if m, _ := path.Match(exclude, path.Base(rel)); m {
+			return true
+		}
+	}
+	return false
+}
+
+// Reads the .gitignore file at the given directory, if any
+func readIgnorePatterns(dir string) []ignorePattern {
+	patterns := make([]ignorePattern, 0)
+
+	file, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return patterns
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		p := ignorePattern{dir: dir}
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		line = strings.TrimPrefix(line, "**/")
+		if strings.Contains(line, "/") {
+			p.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if len(line) > 0 {
+			p.pattern = line
+			patterns = append(patterns, p)
+		}
+	}
+
+	return patterns
+}
+
+// Checks the given path against the patterns, the last matching pattern wins
+func isIgnored(patterns []ignorePattern, file string, dir bool) bool {
+	ignored := false
+	for _, p := range patterns {
+		if p.dirOnly && !dir {
+			continue
+		}
+		rel, err := filepath.Rel(p.dir, file)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+
+		var matched bool
+		if p.anchored {
+			matched, _ = path.Match(p.pattern, rel)
+		} else {
+			matched, _ = path.Match(p.pattern, path.Base(rel))
+		}
+		if matched {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+// Resolves the effective settings of the given tool
+func resolveScannedConfig(config *Config, tool string) map[string]interface{} {
+	settings := make(map[string]interface{})
+	switch tool {
+	case "gradle":
+		settings["replace"] = config.gradle.replace
+		settings["defaults"] = config.gradle.defaults
+		settings["scope"] = config.gradle.scope
+		settings["executable"] = config.gradle.executable
+		settings["version"] = config.gradle.version
+		settings["mismatch"] = config.gradle.mismatch
+		settings["mappings"] = config.gradle.mappings
+	case "maven":
+		settings["replace"] = config.maven.replace
+		settings["defaults"] = config.maven.defaults
+		settings["mvnd"] = config.maven.mvnd
+		settings["scope"] = config.maven.scope
+		settings["executable"] = config.maven.executable
+		settings["version"] = config.maven.version
+		settings["mismatch"] = config.maven.mismatch
+		settings["mvndExclude"] = config.maven.mvndExclude
+		settings["mappings"] = config.maven.mappings
+	case "ant":
+		settings["executable"] = config.ant.executable
+	case "amper":
+		settings["replace"] = config.amper.replace
+		settings["defaults"] = config.amper.defaults
+		settings["mappings"] = config.amper.mappings
+	case "sbt":
+		settings["replace"] = config.sbt.replace
+		settings["defaults"] = config.sbt.defaults
+		settings["mappings"] = config.sbt.mappings
+	case "mill":
+		settings["replace"] = config.mill.replace
+		settings["defaults"] = config.mill.defaults
+		settings["mappings"] = config.mill.mappings
+	case "bazel":
+		settings["replace"] = config.bazel.replace
+		settings["defaults"] = config.bazel.defaults
+		settings["mappings"] = config.bazel.mappings
+	case "clojure":
+		settings["replace"] = config.clojure.replace
+		settings["defaults"] = config.clojure.defaults
+		settings["lein"] = config.clojure.lein
+		settings["deps"] = config.clojure.deps
+	case "bach":
+		settings["version"] = config.bach.version
+	}
+	settings["java"] = config.java.version
+	return settings
+}
+
+// Formats scalar settings as key=value, mappings are only listed in JSON
+func formatScannedConfig(settings map[string]interface{}) string {
+	keys := make([]string, 0, len(settings))
+	for k := range settings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	entries := make([]string, 0)
+	for _, k := range keys {
+		switch v := settings[k].(type) {
+		case bool:
+			entries = append(entries, fmt.Sprintf("%s=%t", k, v))
+		case string:
+			if len(v) > 0 {
+				entries = append(entries, k+"="+v)
+			}
+		}
+	}
+	return orDash(strings.Join(entries, " "))
+}
+
+// Returns the first non empty value, or "-"
+func orDash(values ...string) string {
+	for _, v := range values {
+		if len(v) > 0 {
+			return v
+		}
+	}
+	return "-"
+}
@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScanProjects(t *testing.T) {
+	// given:
+	mavenBin, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "bin"))
+	antBin, _ := filepath.Abs(filepath.Join("..", "tests", "ant", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "scan"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{mavenBin, antBin}}
+
+	config := ReadConfig(context, pwd)
+
+	// when:
+	projects := ScanProjects(context, config)
+
+	// then:
+	if len(projects) != 4 {
+		t.Fatalf("projects: got %v want 4", projects)
+	}
+
+	var checks = []struct {
+		title    string
+		actual   string
+		expected string
+	}{
+		{"GradleDir", projects[0].Dir, "gradle-app"},
+		{"GradleTool", projects[0].Tool, "gradle"},
+		{"GradleBuildFile", projects[0].BuildFile, "gradle-app/build.gradle"},
+		{"GradleSettingsFile", projects[0].SettingsFile, "gradle-app/settings.gradle"},
+		{"GradleWrapper", projects[0].Wrapper, "gradle-app/gradlew"},
+		{"MavenDir", projects[1].Dir, "maven-lib"},
+		{"MavenTool", projects[1].Tool, "maven"},
+		{"MavenBuildFile", projects[1].BuildFile, "maven-lib/pom.xml"},
+		{"MavenWrapper", projects[1].Wrapper, "maven-lib/mvnw"},
+		{"MavenConfigFile", projects[1].ConfigFile, "maven-lib/.gm.toml"},
+		{"MavenReplace", formatScannedConfig(map[string]interface{}{"replace": projects[1].Config["replace"]}), "replace=false"},
+		{"MixedMavenDir", projects[2].Dir, "mixed"},
+		{"MixedMavenTool", projects[2].Tool, "maven"},
+		{"MixedMavenWrapper", projects[2].Wrapper, ""},
+		{"MixedMavenExecutable", projects[2].Executable, filepath.Join(mavenBin, "mvn")},
+		{"MixedAntDir", projects[3].Dir, "mixed"},
+		{"MixedAntTool", projects[3].Tool, "ant"},
+		{"MixedAntBuildFile", projects[3].BuildFile, "mixed/build.xml"},
+	}
+
+	for _, check := range checks {
+		t.Run(check.title, func(t *testing.T) {
+			if check.actual != check.expected {
+				t.Errorf("%s: got %s want %s", check.title, check.actual, check.expected)
+			}
+		})
+	}
+}
+
+func TestScanNestedProjects(t *testing.T) {
+	// given:
+	pwd := t.TempDir()
+	for _, dir := range []string{"core", "tools", "app", "buildSrc", "samples"} {
+		os.Mkdir(filepath.Join(pwd, dir), 0755)
+	}
+	os.WriteFile(filepath.Join(pwd, "pom.xml"), []byte("<project><modules><module>core</module></modules></project>"), 0644)
+	os.WriteFile(filepath.Join(pwd, "core", "pom.xml"), []byte("<project></project>"), 0644)
+	os.WriteFile(filepath.Join(pwd, "tools", "pom.xml"), []byte("<project></project>"), 0644)
+	os.WriteFile(filepath.Join(pwd, "settings.gradle"), []byte("include 'app'\n"), 0644)
+	os.WriteFile(filepath.Join(pwd, "app", "build.gradle"), []byte(""), 0644)
+	os.WriteFile(filepath.Join(pwd, "buildSrc", "build.gradle"), []byte(""), 0644)
+	os.WriteFile(filepath.Join(pwd, "samples", "build.gradle"), []byte(""), 0644)
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: pwd}
+
+	config := ReadConfig(context, pwd)
+
+	// when:
+	projects := ScanProjects(context, config)
+
+	// then:
+	found := make([]string, 0)
+	for _, p := range projects {
+		found = append(found, p.Tool+" "+p.Dir)
+	}
+	actual := strings.Join(found, ", ")
+	expected := "gradle ., maven ., gradle samples, maven tools"
+	if actual != expected {
+		t.Errorf("projects: got %s want %s", actual, expected)
+	}
+}
+
+func TestListProjectsWritesToContext(t *testing.T) {
+	// given:
+	pwd := t.TempDir()
+	empty := t.TempDir()
+	os.WriteFile(filepath.Join(pwd, "build.sbt"), []byte(""), 0644)
+
+	var checks = []struct {
+		title    string
+		dir      string
+		args     []string
+		expected string
+	}{
+		{"Json", pwd, []string{"--json"}, "\"tool\": \"sbt\""},
+		{"Table", pwd, []string{}, "sbt"},
+		{"Empty", empty, []string{}, "Did not find any projects\n"},
+		{"EmptyJson", empty, []string{"--json"}, "[]\n"},
+	}
+
+	for _, check := range checks {
+		t.Run(check.title, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			context := testContext{
+				quiet:      true,
+				workingDir: check.dir,
+				homeDir:    t.TempDir(),
+				stdout:     out}
+
+			// when:
+			args := &ParsedArgs{Gum: map[string]struct{}{}, Tool: check.args, Args: make([]string, 0)}
+			ListProjects(context, args)
+
+			// then:
+			if !strings.Contains(out.String(), check.expected) {
+				t.Errorf("%s: got %q want %q", check.title, out.String(), check.expected)
+			}
+		})
+	}
+}
+
+func TestIgnorePatterns(t *testing.T) {
+	// given:
+	dir, _ := filepath.Abs(filepath.Join("..", "tests", "scan"))
+	patterns := readIgnorePatterns(dir)
+
+	var checks = []struct {
+		title    string
+		file     string
+		isDir    bool
+		expected bool
+	}{
+		{"DirOnly", "generated", true, true},
+		{"DirOnlyNested", filepath.Join("maven-lib", "generated"), true, true},
+		{"DirOnlyFile", "generated", false, false},
+		{"Anchored", filepath.Join("gradle-app", "ignored-module"), true, true},
+		{"AnchoredElsewhere", filepath.Join("maven-lib", "gradle-app", "ignored-module"), true, false},
+		{"NotIgnored", "maven-lib", true, false},
+	}
+
+	for _, check := range checks {
+		t.Run(check.title, func(t *testing.T) {
+			actual := isIgnored(patterns, filepath.Join(dir, check.file), check.isDir)
+			if actual != check.expected {
+				t.Errorf("%s: got %t want %t", check.title, actual, check.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ScalaExt the .scala file extension
+const ScalaExt = ".scala"
+
+// ScExt the .sc file extension
+const ScExt = ".sc"
+
+// KotlinScriptExt the .main.kts file extension
+const KotlinScriptExt = ".main.kts"
+
+// GroovyExt the .groovy file extension
+const GroovyExt = ".groovy"
+
+// scriptSourceExtensions supported script extensions in default discovery order
+var scriptSourceExtensions = []string{ScalaExt, ScExt, KotlinScriptExt, GroovyExt}
+
+// ScriptCommand defines an executable single-file script command (scala-cli, kotlin, groovy)
+type ScriptCommand struct {
+	context            Context
+	config             *Config
+	executable         string
+	launcherArgs       []string
+	args               *ParsedArgs
+	sourceFile         string
+	explicitSourceFile string
+}
+
+// Execute executes the given command
+func (c ScriptCommand) Execute() int {
+	if !trustProject(c.context, c.config) {
+		c.context.Exit(-1)
+	}
+	c.doConfigureScript()
+	return c.doExecuteScript()
+}
+
+func (c *ScriptCommand) doConfigureScript() {
+	c.context.CheckIsExecutable(c.executable)
+
+	args := make([]string, 0)
+
+	banner := make([]string, 0)
+	banner = append(banner, "Using "+filepath.Base(c.executable)+" at '"+c.executable+"'")
+
+	debug := c.args.HasGumFlag("gd")
+
+	if debug {
+		c.config.setDebug(debug)
+	}
+	c.debugConfig()
+	oargs := c.args.Args
+
+	file := c.sourceFile
+	if len(c.explicitSourceFile) > 0 {
+		file = c.explicitSourceFile
+	}
+	banner = append(banner, "to run '"+file+"':")
+
+	args = appendSafe(args, c.launcherArgs)
+	args = appendSafe(args, c.args.Tool)
+	args = append(args, file)
+	if len(oargs) > 0 && isScalaSourceFile(file) {
+		// scala-cli treats positional arguments as additional inputs
+		args = append(args, "--")
+	}
+	c.args.Args = appendSafe(args, oargs)
+
+	c.debugScript(c.config, oargs)
+
+	if !c.config.general.quiet {
+		fmt.Fprintln(c.context.GetStdout(), strings.Join(banner, " "))
+	}
+}
+
+func (c *ScriptCommand) doExecuteScript() int {
+	cmd := exec.Command(c.executable, c.args.Args...)
+	cmd.Stdout = c.context.GetStdout()
+	cmd.Stderr = c.context.GetStderr()
+	cmd.Stdin = os.Stdin
+	err := cmd.Run()
+	var exerr *exec.ExitError
+	if errors.As(err, &exerr) {
+		return exerr.ExitCode()
+	}
+	if err != nil {
+		fmt.Fprintln(c.context.GetStderr(), err)
+		return -1
+	}
+	return 0
+}
+
+func (c *ScriptCommand) debugConfig() {
+	if c.args.HasGumFlag("gc") {
+		c.config.print(c.context.GetStdout())
+		c.context.Exit(0)
+	}
+}
+
+func (c *ScriptCommand) debugScript(config *Config, oargs []string) {
+	if c.config.general.debug {
+		fmt.Fprintln(c.context.GetStdout(), "discovery          = ", config.scripts.discovery)
+		fmt.Fprintln(c.context.GetStdout(), "pwd                = ", c.context.GetWorkingDir())
+		fmt.Fprintln(c.context.GetStdout(), "executable         = ", c.executable)
+		fmt.Fprintln(c.context.GetStdout(), "sourceFile         = ", c.sourceFile)
+		fmt.Fprintln(c.context.GetStdout(), "explicitSourceFile = ", c.explicitSourceFile)
+		fmt.Fprintln(c.context.GetStdout(), "original args      = ", oargs)
+		fmt.Fprintln(c.context.GetStdout(), "actual args        = ", c.args.Args)
+		fmt.Fprintln(c.context.GetStdout(), "")
+	}
+}
+
+// FindScript finds and executes scala-cli/kotlin/groovy
+func FindScript(context Context, args *ParsedArgs) *ScriptCommand {
+	pwd := context.GetWorkingDir()
+
+	explicitSourceFileSet, explicitSourceFile := findExplicitScriptSourceFile(pwd, args)
+
+	config := ReadConfig(context, pwd)
+	sourceFile, noSourceFile := findSourceFile(context, pwd, config.scripts.discovery, scriptSourceExtensions)
+	file := sourceFile
+	if explicitSourceFileSet {
+		file = explicitSourceFile
+	}
+	config = ReadConfig(context, filepath.Dir(file))
+	quiet := args.HasGumFlag("gq")
+
+	if quiet {
+		config.setQuiet(quiet)
+	}
+
+	if !explicitSourceFileSet && noSourceFile != nil {
+		if context.IsExplicit() {
+			fmt.Fprintln(context.GetStdout(), "No script found")
+			fmt.Fprintln(context.GetStdout())
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	executable, launcherArgs, noExecutable := findScriptExec(context, file)
+	if noExecutable != nil {
+		warnNoScriptLauncher(context, config, file)
+
+		if context.IsExplicit() {
+			context.Exit(-1)
+		}
+		return nil
+	}
+
+	if explicitSourceFileSet {
+		return &ScriptCommand{
+			context:            context,
+			config:             config,
+			executable:         executable,
+			launcherArgs:       launcherArgs,
+			args:               args,
+			explicitSourceFile: explicitSourceFile}
+	}
+
+	return &ScriptCommand{
+		context:      context,
+		config:       config,
+		executable:   executable,
+		launcherArgs: launcherArgs,
+		args:         args,
+		sourceFile:   sourceFile}
+}
+
+func warnNoScriptLauncher(context Context, config *Config, file string) {
+	if !config.general.quiet && context.IsExplicit() {
+		if isScalaSourceFile(file) {
+			fmt.Fprintf(context.GetStdout(), "No %s found in path. Please install Scala CLI.", resolveExec(context, "scala-cli"))
+			fmt.Fprintln(context.GetStdout())
+			fmt.Fprintln(context.GetStdout(), "(https://scala-cli.virtuslab.org/install)")
+		} else if strings.HasSuffix(file, KotlinScriptExt) {
+			fmt.Fprintf(context.GetStdout(), "No %s found in path. Please install Kotlin.", resolveExec(context, "kotlin"))
+			fmt.Fprintln(context.GetStdout())
+			fmt.Fprintln(context.GetStdout(), "(https://kotlinlang.org/docs/command-line.html)")
+		} else {
+			fmt.Fprintf(context.GetStdout(), "No %s found in path. Please install Groovy.", resolveExec(context, "groovy"))
+			fmt.Fprintln(context.GetStdout())
+			fmt.Fprintln(context.GetStdout(), "(https://groovy.apache.org/download.html)")
+		}
+		fmt.Fprintln(context.GetStdout())
+	}
+}
+
+// Finds the launcher for the given script and any arguments it requires
+func findScriptExec(context Context, file string) (string, []string, error) {
+	if isScalaSourceFile(file) {
+		scalacli, noScalacli := findExecutable(context, "", "scala-cli")
+		if noScalacli == nil {
+			return scalacli, []string{"run"}, nil
+		}
+		// Scala 3.5+ ships scala-cli as the scala command
+		scala, noScala := findExecutable(context, "", "scala")
+		if noScala == nil {
+			return scala, []string{"run"}, nil
+		}
+		return "", nil, noScalacli
+	} else if strings.HasSuffix(file, KotlinScriptExt) {
+		kotlin, noKotlin := findExecutable(context, "", "kotlin")
+		return kotlin, []string{}, noKotlin
+	}
+
+	groovy, noGroovy := findExecutable(context, "", "groovy")
+	return groovy, []string{}, noGroovy
+}
+
+func isScalaSourceFile(source string) bool {
+	return strings.HasSuffix(source, ScalaExt) || strings.HasSuffix(source, ScExt)
+}
+
+func isScriptSourceFile(source string) bool {
+	for _, ext := range scriptSourceExtensions {
+		if strings.HasSuffix(source, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// Finds an explicit script given as the first non flag arg and removes it from args
+func findExplicitScriptSourceFile(pwd string, args *ParsedArgs) (bool, string) {
+	for i := range args.Args {
+		arg := args.Args[i]
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if isScriptSourceFile(arg) {
+			args.Args = shrinkSlice(args.Args, i, 1)
+			if filepath.IsAbs(arg) {
+				return true, arg
+			}
+			return true, filepath.Join(pwd, arg)
+		}
+		break
+	}
+
+	return false, ""
+}
+
+// Resolves the extension of a file name, taking double extensions into account
+func resolveSourceExtension(name string) string {
+	if strings.HasSuffix(name, KotlinScriptExt) {
+		return KotlinScriptExt
+	}
+	return path.Ext(name)
+}
+
+// Resolves a discovery choice such as "java", ".java", or "main.kts" into an extension
+func resolveDiscoveryExtension(choice string, supported []string) (string, bool) {
+	ext := "." + strings.TrimPrefix(strings.TrimSpace(strings.ToLower(choice)), ".")
+	for _, e := range supported {
+		if e == ext {
+			return e, true
+		}
+	}
+	return "", false
+}
+
+// Finds the first source file in dir following the given discovery order.
+// The supported extensions define the default order when discovery is empty.
+func findSourceFile(context Context, dir string, discovery []string, supported []string) (string, error) {
+	files, err := ioutil.ReadDir(dir)
+
+	if err != nil {
+		return "", err
+	}
+
+	choices := make(map[string]string)
+
+	for i := range files {
+		file := files[i]
+		if file.IsDir() || file.Name() == "build.sc" {
+			// build.sc belongs to Mill
+			continue
+		}
+		extension := resolveSourceExtension(file.Name())
+		if extension == "" {
+			continue
+		}
+		_, exists := choices[extension]
+		if !exists {
+			choices[extension] = file.Name()
+		}
+	}
+
+	order := supported
+	if len(discovery) > 0 {
+		order = make([]string, len(discovery))
+		for i := range discovery {
+			ext, ok := resolveDiscoveryExtension(discovery[i], supported)
+			if !ok {
+				fmt.Fprintln(context.GetStdout(), "Unsupported extension: "+discovery[i])
+				context.Exit(-1)
+			}
+			order[i] = ext
+		}
+	}
+
+	for _, ext := range order {
+		file, exists := choices[ext]
+		if exists {
+			return filepath.Join(dir, file), nil
+		}
+	}
+
+	return "", errors.New("Did not find a launchable source")
+}
@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScriptScala(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "scripts", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "scripts", "scala"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "foo"})
+	cmd := FindScript(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "scala-cli")},
+		{"SourceFile", cmd.sourceFile, filepath.Join(pwd, "zzz.scala")},
+		{"ExplicitSourceFile", cmd.explicitSourceFile, ""},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+
+	cmd.doConfigureScript()
+	expected := "run " + filepath.Join(pwd, "zzz.scala") + " -- foo"
+	if strings.Join(cmd.args.Args, " ") != expected {
+		t.Errorf("args: got %s, want %s", cmd.args.Args, expected)
+	}
+}
+
+func TestScriptScalaWithExplicitFile(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "scripts", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "scripts", "scala"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "hello.sc"})
+	cmd := FindScript(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "scala-cli")},
+		{"SourceFile", cmd.sourceFile, ""},
+		{"ExplicitSourceFile", cmd.explicitSourceFile, filepath.Join(pwd, "hello.sc")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+
+	cmd.doConfigureScript()
+	if len(cmd.args.Args) != 2 {
+		t.Errorf("args: got %s, want [run hello.sc]", cmd.args.Args)
+	}
+}
+
+func TestScriptKotlin(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "scripts", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "scripts", "kotlin"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "foo"})
+	cmd := FindScript(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "kotlin")},
+		{"SourceFile", cmd.sourceFile, filepath.Join(pwd, "hello.main.kts")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+
+	cmd.doConfigureScript()
+	expected := filepath.Join(pwd, "hello.main.kts") + " foo"
+	if strings.Join(cmd.args.Args, " ") != expected {
+		t.Errorf("args: got %s, want %s", cmd.args.Args, expected)
+	}
+}
+
+func TestScriptGroovy(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "scripts", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "scripts", "groovy"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindScript(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"Executable", cmd.executable, filepath.Join(bin, "groovy")},
+		{"SourceFile", cmd.sourceFile, filepath.Join(pwd, "hello.groovy")},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestScriptWithConfiguredDiscovery(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "scripts", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "scripts", "mixed"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindScript(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	if cmd.sourceFile != filepath.Join(pwd, "hello.groovy") {
+		t.Errorf("SourceFile: got %s, want %s", cmd.sourceFile, filepath.Join(pwd, "hello.groovy"))
+	}
+}
+
+func TestScriptWithoutExecutables(t *testing.T) {
+	// given:
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "scripts", "scala"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{}}
+
+	// when:
+	args := ParseArgs([]string{"-gq"})
+	cmd := FindScript(context, &args)
+
+	// then:
+	if cmd != nil {
+		t.Error("Expected a nil command but got something")
+	}
+}
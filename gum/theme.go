@@ -18,6 +18,7 @@ package gum
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/gookit/color"
@@ -55,66 +56,85 @@ type ColoredTheme struct {
 }
 
 // PrintSection prints a section header such as [section]
-func (t *ColoredTheme) PrintSection(section string) {
-	t.symbol.Print("[")
-	t.section.Print(section)
-	t.symbol.Println("]")
+func (t *ColoredTheme) PrintSection(out io.Writer, section string) {
+	fmt.Fprint(out, t.symbol.Sprint("["))
+	fmt.Fprint(out, t.section.Sprint(section))
+	fmt.Fprintln(out, t.symbol.Sprint("]"))
 }
 
 // PrintKeyValueBoolean prints a key/value pair as key = value
-func (t *ColoredTheme) PrintKeyValueBoolean(key string, value bool) {
-	t.key.Print(key)
-	t.symbol.Print(" = ")
-	t.boolean.Println(value)
+func (t *ColoredTheme) PrintKeyValueBoolean(out io.Writer, key string, value bool) {
+	fmt.Fprint(out, t.key.Sprint(key))
+	fmt.Fprint(out, t.symbol.Sprint(" = "))
+	fmt.Fprintln(out, t.boolean.Sprint(value))
 }
 
 // PrintKeyValueLiteral prints a key/value pair as key = "value"
-func (t *ColoredTheme) PrintKeyValueLiteral(key string, value string) {
-	t.key.Print(key)
-	t.symbol.Print(" = \"")
-	t.literal.Print(value)
-	t.literal.Println("\"")
+func (t *ColoredTheme) PrintKeyValueLiteral(out io.Writer, key string, value string) {
+	fmt.Fprint(out, t.key.Sprint(key))
+	fmt.Fprint(out, t.symbol.Sprint(" = \""))
+	fmt.Fprint(out, t.literal.Sprint(value))
+	fmt.Fprintln(out, t.literal.Sprint("\""))
 }
 
 // PrintKeyValueArrayS prints a key/value pair as key = ["v1", "v2"]
-func (t *ColoredTheme) PrintKeyValueArrayS(key string, value []string) {
-	t.key.Print(key)
-	t.symbol.Print(" = [")
+func (t *ColoredTheme) PrintKeyValueArrayS(out io.Writer, key string, value []string) {
+	fmt.Fprint(out, t.key.Sprint(key))
+	fmt.Fprint(out, t.symbol.Sprint(" = ["))
 
 	for i, w := range value {
 		if i != 0 {
-			t.symbol.Print(", ")
+			fmt.Fprint(out, t.symbol.Sprint(", "))
 		}
-		t.literal.Print("\"")
-		t.literal.Print(w)
-		t.literal.Print("\"")
+		fmt.Fprint(out, t.literal.Sprint("\""))
+		fmt.Fprint(out, t.literal.Sprint(w))
+		fmt.Fprint(out, t.literal.Sprint("\""))
 	}
 
-	t.symbol.Println("]")
+	fmt.Fprintln(out, t.symbol.Sprint("]"))
 }
 
 // PrintKeyValueArrayI prints a key/value pair as key = [i1, i2]
-func (t *ColoredTheme) PrintKeyValueArrayI(key string, value [2]uint8) {
-	t.key.Print(key)
-	t.symbol.Print(" = [")
-	t.symbol.Print(value[0])
-	t.symbol.Print(", ")
-	t.symbol.Print(value[1])
-	t.symbol.Println("]")
+func (t *ColoredTheme) PrintKeyValueArrayI(out io.Writer, key string, value [2]uint8) {
+	fmt.Fprint(out, t.key.Sprint(key))
+	fmt.Fprint(out, t.symbol.Sprint(" = ["))
+	fmt.Fprint(out, t.symbol.Sprint(value[0]))
+	fmt.Fprint(out, t.symbol.Sprint(", "))
+	fmt.Fprint(out, t.symbol.Sprint(value[1]))
+	fmt.Fprintln(out, t.symbol.Sprint("]"))
 }
 
 // PrintMap prints a map with each entry as key = "value"
-func (t *ColoredTheme) PrintMap(value map[string]string) {
+func (t *ColoredTheme) PrintMap(out io.Writer, value map[string]string) {
 	for k, v := range value {
 		if strings.Contains(k, ":") {
-			t.key.Print("\"" + k + "\"")
+			fmt.Fprint(out, t.key.Sprint("\""+k+"\""))
 		} else {
-			t.key.Print(k)
+			fmt.Fprint(out, t.key.Sprint(k))
 		}
-		t.symbol.Print(" = ")
-		t.literal.Print("\"")
-		t.literal.Print(v)
-		t.literal.Println("\"")
+		fmt.Fprint(out, t.symbol.Sprint(" = "))
+		fmt.Fprint(out, t.literal.Sprint("\""))
+		fmt.Fprint(out, t.literal.Sprint(v))
+		fmt.Fprintln(out, t.literal.Sprint("\""))
+	}
+}
+
+// PrintTable prints rows aligned in columns below the given headers
+func (t *ColoredTheme) PrintTable(out io.Writer, headers []string, rows [][]string) {
+	widths := resolveColumnWidths(headers, rows)
+	for i, h := range headers {
+		fmt.Fprint(out, t.key.Sprint(padColumn(h, widths, i)))
+	}
+	fmt.Fprintln(out)
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == 0 {
+				fmt.Fprint(out, t.section.Sprint(padColumn(cell, widths, i)))
+			} else {
+				fmt.Fprint(out, t.literal.Sprint(padColumn(cell, widths, i)))
+			}
+		}
+		fmt.Fprintln(out)
 	}
 }
 
@@ -122,62 +142,100 @@ type noneTheme struct {
 }
 
 // PrintSection prints a section header such as [section]
-func (t *noneTheme) PrintSection(section string) {
-	fmt.Println("[" + section + "]")
+func (t *noneTheme) PrintSection(out io.Writer, section string) {
+	fmt.Fprintln(out, "["+section+"]")
 }
 
 // PrintKeyValueBoolean prints a key/value pair as key = value
-func (t *noneTheme) PrintKeyValueBoolean(key string, value bool) {
-	fmt.Println(key+" =", value)
+func (t *noneTheme) PrintKeyValueBoolean(out io.Writer, key string, value bool) {
+	fmt.Fprintln(out, key+" =", value)
 }
 
 // PrintKeyValueLiteral prints a key/value pair as key = "value"
-func (t *noneTheme) PrintKeyValueLiteral(key string, value string) {
-	fmt.Print(key)
-	fmt.Print(" = \"")
-	fmt.Print(value)
-	fmt.Println("\"")
+func (t *noneTheme) PrintKeyValueLiteral(out io.Writer, key string, value string) {
+	fmt.Fprint(out, key)
+	fmt.Fprint(out, " = \"")
+	fmt.Fprint(out, value)
+	fmt.Fprintln(out, "\"")
 }
 
 // PrintKeyValueArrayS prints a key/value pair as key = ["v1", "v2"]
-func (t *noneTheme) PrintKeyValueArrayS(key string, value []string) {
-	fmt.Print(key)
-	fmt.Print(" = ")
-	fmt.Print("[")
+func (t *noneTheme) PrintKeyValueArrayS(out io.Writer, key string, value []string) {
+	fmt.Fprint(out, key)
+	fmt.Fprint(out, " = ")
+	fmt.Fprint(out, "[")
 
 	for i, w := range value {
 		if i != 0 {
-			fmt.Print(", ")
+			fmt.Fprint(out, ", ")
 		}
-		fmt.Print("\"")
-		fmt.Print(w)
-		fmt.Print("\"")
+		fmt.Fprint(out, "\"")
+		fmt.Fprint(out, w)
+		fmt.Fprint(out, "\"")
 	}
 
-	fmt.Println("]")
+	fmt.Fprintln(out, "]")
 }
 
 // PrintKeyValueArrayI prints a key/value pair as key = [i1, i2]
-func (t *noneTheme) PrintKeyValueArrayI(key string, value [2]uint8) {
-	fmt.Print(key)
-	fmt.Print(" = [")
-	fmt.Print(value[0])
-	fmt.Print(", ")
-	fmt.Print(value[1])
-	fmt.Println("]")
+func (t *noneTheme) PrintKeyValueArrayI(out io.Writer, key string, value [2]uint8) {
+	fmt.Fprint(out, key)
+	fmt.Fprint(out, " = [")
+	fmt.Fprint(out, value[0])
+	fmt.Fprint(out, ", ")
+	fmt.Fprint(out, value[1])
+	fmt.Fprintln(out, "]")
 }
 
 // PrintMap prints a map with each entry as key = "value"
-func (t *noneTheme) PrintMap(value map[string]string) {
+func (t *noneTheme) PrintMap(out io.Writer, value map[string]string) {
 	for k, v := range value {
 		if strings.Contains(k, ":") {
-			fmt.Print("\"" + k + "\"")
+			fmt.Fprint(out, "\""+k+"\"")
 		} else {
-			fmt.Print(k)
+			fmt.Fprint(out, k)
+		}
+		fmt.Fprint(out, " = ")
+		fmt.Fprint(out, "\"")
+		fmt.Fprint(out, v)
+		fmt.Fprintln(out, "\"")
+	}
+}
+
+// PrintTable prints rows aligned in columns below the given headers
+func (t *noneTheme) PrintTable(out io.Writer, headers []string, rows [][]string) {
+	widths := resolveColumnWidths(headers, rows)
+	for i, h := range headers {
+		fmt.Fprint(out, padColumn(h, widths, i))
+	}
+	fmt.Fprintln(out)
+	for _, row := range rows {
+		for i, cell := range row {
+			fmt.Fprint(out, padColumn(cell, widths, i))
 		}
-		fmt.Print(" = ")
-		fmt.Print("\"")
-		fmt.Print(v)
-		fmt.Println("\"")
+		fmt.Fprintln(out)
+	}
+}
+
+func resolveColumnWidths(headers []string, rows [][]string) []int {
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = len(h)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i < len(widths) && len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+	return widths
+}
+
+// Pads a cell to the width of its column, the last column is not padded
+func padColumn(cell string, widths []int, i int) string {
+	if i >= len(widths)-1 {
+		return cell
 	}
+	return fmt.Sprintf("%-*s  ", widths[i], cell)
 }
@@ -17,94 +17,269 @@
 package gum
 
 import (
+	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 )
 
-// FindTool Executes gradle/maven/ant/bach/jbang based on config discovery
+var defaultToolDiscovery = []string{"gradle", "maven", "ant", "amper", "sbt", "mill", "bazel", "clojure", "bach", "jbang", "scripts"}
+
+// toolCandidate is a tool able to run the build at the current directory
+type toolCandidate struct {
+	tool string
+	// nearest build file, used to resolve ambiguities
+	buildFile    string
+	settingsFile string
+	executable   string
+	// fallbacks (jbang, scripts) are only used when no build tool is found
+	fallback bool
+	config   *Config
+	// the resolved command, e.g. *GradleCommand
+	command interface{}
+	execute func() int
+}
+
+// FindTool Executes gradle/maven/ant/amper/sbt/mill/bazel/clojure/bach/jbang/scripts based on config discovery
 func FindTool(args *ParsedArgs) {
 	context := NewDefaultContext(false)
 	config := ReadUserConfig(context)
 	config.merge(nil)
 
-	if len(config.general.discovery) == 5 {
-		discoverTool(config, context, args)
+	candidates := findToolCandidates(context, config, args)
+	selected := selectToolCandidates(context, config, candidates)
+
+	if args.HasGumFlag("gd") {
+		for _, candidate := range candidates {
+			fmt.Println("tool candidate     = ", candidate.tool+" "+candidate.buildFile)
+		}
+		fmt.Println("")
 	}
 
-	doFindGradle(context, args)
-	doFindMaven(context, args)
-	doFindAnt(context, args)
-	doFindBach(context, args)
-	doFindJbang(context, args)
+	if len(selected) == 1 {
+		os.Exit(selected[0].execute())
+	} else if len(selected) > 1 {
+		os.Exit(resolveAmbiguousTool(selected).execute())
+	}
 
 	if args.HasGumFlag("gc") {
-		config.print()
+		config.print(os.Stdout)
 		os.Exit(0)
 	} else {
-		fmt.Println("Did not find a Gradle, Maven, Bach, JBang or Ant project")
+		fmt.Println("Did not find a Gradle, Maven, Amper, sbt, Mill, Bazel, Clojure, Bach, JBang, script or Ant project")
 		os.Exit(-1)
 	}
 }
 
-func discoverTool(config *Config, context Context, args *ParsedArgs) {
-	for i := range config.general.discovery {
-		tool := strings.TrimSpace(strings.ToLower(config.general.discovery[i]))
-
-		switch tool {
-		case "gradle":
-			doFindGradle(context, args)
-			break
-		case "maven":
-			doFindMaven(context, args)
-			break
-		case "jbang":
-			doFindJbang(context, args)
-			break
-		case "bach":
-			doFindBach(context, args)
-			break
-		case "ant":
-			doFindAnt(context, args)
-			break
-		default:
-			fmt.Println("Unsupported tool: " + tool)
-			os.Exit(-1)
+// Collects every tool able to run the build at the current directory, in discovery order
+func findToolCandidates(context Context, config *Config, args *ParsedArgs) []toolCandidate {
+	// configured tools come first, followed by the remaining ones in default order
+	discovery := make([]string, 0)
+	discovery = append(discovery, config.general.discovery...)
+	for _, tool := range defaultToolDiscovery {
+		found := false
+		for _, d := range config.general.discovery {
+			if strings.TrimSpace(strings.ToLower(d)) == tool {
+				found = true
+			}
+		}
+		if !found {
+			discovery = append(discovery, tool)
 		}
 	}
+
+	candidates := make([]toolCandidate, 0)
+	for i := range discovery {
+		tool := strings.TrimSpace(strings.ToLower(discovery[i]))
+		// each finder consumes the flags it recognizes from its own copy
+		candidate := findToolCandidate(context, args.copy(), tool)
+		if candidate != nil {
+			candidates = append(candidates, *candidate)
+		}
+	}
+
+	return candidates
 }
 
-func doFindGradle(context Context, args *ParsedArgs) {
-	gradle := FindGradle(context, args)
-	if gradle != nil {
-		os.Exit(gradle.Execute())
+func findToolCandidate(context Context, args *ParsedArgs, tool string) *toolCandidate {
+	switch tool {
+	case "gradle":
+		if c := FindGradle(context, args); c != nil {
+			buildFile := c.buildFile
+			if len(buildFile) == 0 {
+				buildFile = c.settingsFile
+			}
+			return &toolCandidate{tool: tool, buildFile: buildFile, settingsFile: c.settingsFile, executable: c.executable, config: c.config, command: c, execute: c.Execute}
+		}
+	case "maven":
+		if c := FindMaven(context, args); c != nil {
+			return &toolCandidate{tool: tool, buildFile: c.buildFile, executable: c.executable, config: c.config, command: c, execute: c.Execute}
+		}
+	case "jbang":
+		if c := FindJbang(context, args); c != nil {
+			return &toolCandidate{tool: tool, buildFile: c.sourceFile, fallback: true, executable: c.executable, config: c.config, command: c, execute: c.Execute}
+		}
+	case "scripts":
+		if c := FindScript(context, args); c != nil {
+			return &toolCandidate{tool: tool, buildFile: c.sourceFile, fallback: true, executable: c.executable, config: c.config, command: c, execute: c.Execute}
+		}
+	case "bach":
+		if c := FindBach(context, args); c != nil {
+			return &toolCandidate{tool: tool, buildFile: filepath.Join(c.rootdir, ".bach"), executable: c.executable, config: c.config, command: c, execute: c.Execute}
+		}
+	case "ant":
+		if c := FindAnt(context, args); c != nil {
+			return &toolCandidate{tool: tool, buildFile: c.buildFile, executable: c.executable, config: c.config, command: c, execute: c.Execute}
+		}
+	case "amper":
+		if c := FindAmper(context, args); c != nil {
+			buildFile := c.moduleFile
+			if len(buildFile) == 0 {
+				buildFile = c.projectFile
+			}
+			return &toolCandidate{tool: tool, buildFile: buildFile, executable: c.executable, config: c.config, command: c, execute: c.Execute}
+		}
+	case "sbt":
+		if c := FindSbt(context, args); c != nil {
+			return &toolCandidate{tool: tool, buildFile: c.buildFile, executable: c.executable, config: c.config, command: c, execute: c.Execute}
+		}
+	case "mill":
+		if c := FindMill(context, args); c != nil {
+			return &toolCandidate{tool: tool, buildFile: c.buildFile, executable: c.executable, config: c.config, command: c, execute: c.Execute}
+		}
+	case "bazel":
+		if c := FindBazel(context, args); c != nil {
+			return &toolCandidate{tool: tool, buildFile: c.workspaceFile, executable: c.executable, config: c.config, command: c, execute: c.Execute}
+		}
+	case "clojure":
+		if c := FindClojure(context, args); c != nil {
+			return &toolCandidate{tool: tool, buildFile: c.buildFile, executable: c.executable, config: c.config, command: c, execute: c.Execute}
+		}
+	default:
+		fmt.Fprintln(context.GetStdout(), "Unsupported tool: "+tool)
+		context.Exit(-1)
 	}
+
+	return nil
 }
 
-func doFindMaven(context Context, args *ParsedArgs) {
-	maven := FindMaven(context, args)
-	if maven != nil {
-		os.Exit(maven.Execute())
+// Selects the candidates that may run the build, more than one means the choice is ambiguous.
+// A tool pinned with [general] tool wins, then the tools whose build file is nearest to the
+// current directory. Ties are broken by the configured discovery order, if any.
+// Fallbacks are only selected when there are no other candidates.
+func selectToolCandidates(context Context, config *Config, candidates []toolCandidate) []toolCandidate {
+	tools := make([]toolCandidate, 0)
+	fallbacks := make([]toolCandidate, 0)
+	for _, candidate := range candidates {
+		if candidate.fallback {
+			fallbacks = append(fallbacks, candidate)
+		} else {
+			tools = append(tools, candidate)
+		}
 	}
+
+	if len(tools) == 0 {
+		if len(fallbacks) > 0 {
+			return fallbacks[:1]
+		}
+		return tools
+	}
+
+	for _, candidate := range candidates {
+		pin := candidate.config.general.tool
+		if len(pin) == 0 {
+			continue
+		}
+		for _, tool := range candidates {
+			if tool.tool == pin {
+				return []toolCandidate{tool}
+			}
+		}
+	}
+
+	tools = dropBazelNestedCandidates(tools)
+
+	pwd := context.GetWorkingDir()
+	nearest := make([]toolCandidate, 0)
+	distance := -1
+	for _, candidate := range tools {
+		d := resolveBuildFileDistance(pwd, candidate.buildFile)
+		if distance < 0 || d < distance {
+			nearest = []toolCandidate{candidate}
+			distance = d
+		} else if d == distance {
+			nearest = append(nearest, candidate)
+		}
+	}
+
+	// candidates are sorted in discovery order
+	if len(nearest) > 1 {
+		for _, tool := range config.general.discovery {
+			if strings.TrimSpace(strings.ToLower(tool)) == nearest[0].tool {
+				return nearest[:1]
+			}
+		}
+	}
+
+	return nearest
 }
 
-func doFindJbang(context Context, args *ParsedArgs) {
-	jbang := FindJbang(context, args)
-	if jbang != nil {
-		os.Exit(jbang.Execute())
+// Drops the candidates whose build file lies below the root of a Bazel workspace found as well,
+// such as a leaf pom.xml kept for publishing, as Bazel builds the whole workspace
+func dropBazelNestedCandidates(tools []toolCandidate) []toolCandidate {
+	workspace := ""
+	for _, candidate := range tools {
+		if candidate.tool == "bazel" {
+			workspace = filepath.Dir(candidate.buildFile)
+		}
+	}
+	if len(workspace) == 0 {
+		return tools
+	}
+
+	kept := make([]toolCandidate, 0)
+	for _, candidate := range tools {
+		rel, err := filepath.Rel(workspace, filepath.Dir(candidate.buildFile))
+		if candidate.tool != "bazel" && err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			continue
+		}
+		kept = append(kept, candidate)
 	}
+	return kept
 }
 
-func doFindBach(context Context, args *ParsedArgs) {
-	bach := FindBach(context, args)
-	if bach != nil {
-		os.Exit(bach.Execute())
+// Counts the directories between the current directory and the one holding the given build file
+func resolveBuildFileDistance(pwd string, buildFile string) int {
+	rel, err := filepath.Rel(filepath.Dir(buildFile), pwd)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return int(^uint(0) >> 1)
+	}
+	if rel == "." {
+		return 0
 	}
+	return len(strings.Split(rel, string(filepath.Separator)))
 }
 
-func doFindAnt(context Context, args *ParsedArgs) {
-	ant := FindAnt(context, args)
-	if ant != nil {
-		os.Exit(ant.Execute())
+// Asks which tool should run the build, or lists the candidates and exits when not interactive
+func resolveAmbiguousTool(candidates []toolCandidate) toolCandidate {
+	fmt.Println("Found several tools able to run this build:")
+	for i, candidate := range candidates {
+		fmt.Printf("  %d) %s (%s)", i+1, candidate.tool, candidate.buildFile)
+		fmt.Println()
+	}
+
+	if isTerminal(os.Stdin) {
+		fmt.Printf("Which one should run it? [1-%d] ", len(candidates))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		i, err := strconv.Atoi(strings.TrimSpace(answer))
+		if err == nil && i > 0 && i <= len(candidates) {
+			return candidates[i-1]
+		}
 	}
+
+	fmt.Println("Use a flag such as -gg or -gm, or set tool in the [general] section of the project's .gm.toml")
+	os.Exit(-1)
+	return toolCandidate{}
 }
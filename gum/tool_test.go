@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestToolSelection(t *testing.T) {
+	// given:
+	gradleBin, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "bin"))
+	mavenBin, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "bin"))
+	bazelBin, _ := filepath.Abs(filepath.Join("..", "tests", "bazel", "bin"))
+	root, _ := filepath.Abs(filepath.Join("..", "tests", "tool"))
+
+	var checks = []struct {
+		title     string
+		dir       string
+		discovery []string
+		expected  []string
+	}{
+		{"SameDir", "same-dir", nil, []string{"gradle", "maven"}},
+		{"SameDirWithDiscovery", "same-dir", []string{"maven"}, []string{"maven"}},
+		{"Nested", filepath.Join("nested", "module"), nil, []string{"maven"}},
+		{"Pinned", "pinned", nil, []string{"maven"}},
+		{"BazelWorkspace", filepath.Join("..", "bazel", "module", "path", "to", "pkg"), nil, []string{"bazel"}},
+	}
+
+	for _, check := range checks {
+		pwd := filepath.Join(root, check.dir)
+		context := testContext{
+			quiet:      true,
+			explicit:   false,
+			windows:    false,
+			workingDir: pwd,
+			paths:      []string{gradleBin, mavenBin, bazelBin}}
+
+		config := newConfig()
+		config.general.discovery = check.discovery
+		config.merge(nil)
+
+		// when:
+		args := ParseArgs([]string{"-gq", "build"})
+		candidates := findToolCandidates(context, config, &args)
+		selected := selectToolCandidates(context, config, candidates)
+
+		// then:
+		if len(selected) != len(check.expected) {
+			t.Errorf("%s: got %d candidates, want %d", check.title, len(selected), len(check.expected))
+			continue
+		}
+		for i, candidate := range selected {
+			if candidate.tool != check.expected[i] {
+				t.Errorf("%s: got %s, want %s", check.title, candidate.tool, check.expected[i])
+			}
+		}
+	}
+}
+
+func TestToolCandidatesKeepArgs(t *testing.T) {
+	// given:
+	gradleBin, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "bin"))
+	mavenBin, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "tool", "same-dir"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   false,
+		windows:    false,
+		workingDir: pwd,
+		paths:      []string{gradleBin, mavenBin}}
+
+	config := newConfig()
+	config.merge(nil)
+
+	// when:
+	args := ParseArgs([]string{"-gq", "-f", "pom.xml", "-p", pwd, "build"})
+	candidates := findToolCandidates(context, config, &args)
+
+	// then:
+	if len(candidates) != 2 {
+		t.Errorf("candidates: got %d, want 2", len(candidates))
+	}
+	actual := strings.Join(args.Tool, " ")
+	expected := "-f pom.xml -p " + pwd
+	if actual != expected {
+		t.Errorf("args: got %s, want %s", actual, expected)
+	}
+}
+
+func TestBuildFileDistance(t *testing.T) {
+	// given:
+	root := filepath.Join(string(filepath.Separator), "project")
+
+	var checks = []struct {
+		buildFile string
+		expected  int
+	}{
+		{filepath.Join(root, "a", "b", "pom.xml"), 0},
+		{filepath.Join(root, "a", "build.gradle"), 1},
+		{filepath.Join(root, "build.xml"), 2},
+	}
+
+	for _, check := range checks {
+		// when:
+		actual := resolveBuildFileDistance(filepath.Join(root, "a", "b"), check.buildFile)
+
+		// then:
+		if actual != check.expected {
+			t.Errorf("%s: got %d, want %d", check.buildFile, actual, check.expected)
+		}
+	}
+}
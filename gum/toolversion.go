@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var gradleDistributionVersionPattern = regexp.MustCompile(`gradle-(\d+(?:\.\d+)*(?:-[\w.]+?)?)-(?:bin|all)\.zip`)
+var gradleVersionOutputPattern = regexp.MustCompile(`(?m)^Gradle (\d+(?:\.\d+)*\S*)`)
+var mavenDistributionVersionPattern = regexp.MustCompile(`apache-maven-(\d+(?:\.\d+)*(?:-[\w.-]+?)?)-bin\.zip`)
+var mavenVersionOutputPattern = regexp.MustCompile(`(?m)^Apache Maven (\d+(?:\.\d+)*\S*)`)
+
+// toolVersion captures the build tool version a project expects and the one at hand
+type toolVersion struct {
+	// version the project expects
+	expected string
+	// where the expected version was read from
+	source string
+	// version reported by the system tool, empty when running a wrapper
+	detected string
+}
+
+// String returns a textual representation of the expected version
+func (v *toolVersion) String() string {
+	if len(v.expected) == 0 {
+		return ""
+	}
+	return v.expected + " (" + v.source + ")"
+}
+
+// Finds the build tool version a project expects.
+// Checks the following sources in order:
+// - distributionUrl in the nearest wrapper properties file
+// - the candidate in the nearest .sdkmanrc
+// - the configured version
+func findExpectedToolVersion(context Context, dir string, properties string, pattern *regexp.Regexp, candidate string, configured string) *toolVersion {
+	path := findFileUpwards(context, dir, properties)
+	if len(path) > 0 {
+		matches := pattern.FindStringSubmatch(readProperties(path)["distributionUrl"])
+		if matches != nil {
+			return &toolVersion{expected: matches[1], source: path}
+		}
+	}
+
+	path = findFileUpwards(context, dir, ".sdkmanrc")
+	if len(path) > 0 {
+		version := readProperties(path)[candidate]
+		if len(version) > 0 {
+			return &toolVersion{expected: version, source: path}
+		}
+	}
+
+	if len(configured) > 0 {
+		return &toolVersion{expected: configured, source: "[" + candidate + "] version"}
+	}
+
+	return &toolVersion{}
+}
+
+// Finds the given relative path from dir upwards
+func findFileUpwards(context Context, dir string, path string) string {
+	for {
+		file := filepath.Join(dir, path)
+		if context.FileExists(file) {
+			return file
+		}
+
+		parentdir, ok := resolveSearchParent(context, dir)
+		if !ok {
+			return ""
+		}
+		dir = parentdir
+	}
+}
+
+// Runs the given executable with --version and parses its output with the given pattern
+func readToolVersion(executable string, env []string, pattern *regexp.Regexp) string {
+	cmd := exec.Command(executable, "--version")
+	cmd.Env = env
+	output, err := cmd.Output()
+	if err != nil && len(output) == 0 {
+		return ""
+	}
+
+	matches := pattern.FindSubmatch(output)
+	if matches == nil {
+		return ""
+	}
+	return string(matches[1])
+}
+
+// Checks the detected version against the expected one, returning false if the build must not run.
+// Only major versions are compared.
+func (v *toolVersion) check(context Context, config *Config, tool string, mismatch string) bool {
+	if mismatch == "off" || len(v.expected) == 0 || len(v.detected) == 0 {
+		return true
+	}
+
+	if toolMajorVersion(v.expected) == toolMajorVersion(v.detected) {
+		return true
+	}
+
+	if mismatch == "fail" {
+		fmt.Fprintf(context.GetStdout(), "%s %s does not match the expected version %s from %s", tool, v.detected, v.expected, v.source)
+		fmt.Fprintln(context.GetStdout())
+		return false
+	}
+
+	if !config.general.quiet {
+		fmt.Fprintf(context.GetStdout(), "WARNING: %s %s does not match the expected version %s from %s", tool, v.detected, v.expected, v.source)
+		fmt.Fprintln(context.GetStdout())
+	}
+	return true
+}
+
+func toolMajorVersion(version string) string {
+	return strings.Split(versionPattern.FindString(version), ".")[0]
+}
@@ -0,0 +1,316 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Checks that the project config and the given project local wrappers, along with the files they read, are trusted.
+// Files are trusted once allowed with -g-allow, or when accepted at the prompt. Trust is revoked when
+// they change or their directory is denied with -g-deny. Project settings withheld while the project
+// config was untrusted are applied once it is trusted.
+func trustProject(context Context, config *Config, wrappers ...string) bool {
+	if !confirmTrustedFiles(context, config, wrappers...) {
+		return false
+	}
+	config.restoreWithheld()
+	return true
+}
+
+func confirmTrustedFiles(context Context, config *Config, wrappers ...string) bool {
+	if config.general.trust == "always" {
+		return true
+	}
+
+	files := make([]string, 0)
+	if len(config.projectFile) > 0 {
+		files = append(files, config.projectFile)
+	}
+	files = append(files, wrappers...)
+
+	untrusted := make([]string, 0)
+	checksums := make(map[string]string)
+	for _, file := range files {
+		dir := filepath.Dir(file)
+		if isTrustDenied(context, dir) {
+			fmt.Fprintf(context.GetStdout(), "%s is denied. Run `gm -g-allow` to allow it", dir)
+			fmt.Fprintln(context.GetStdout())
+			return false
+		}
+
+		checksum, err := sha256File(file)
+		if err != nil {
+			continue
+		}
+
+		recorded, ok := readTrustedFiles(context, dir)[file]
+		if !ok {
+			untrusted = append(untrusted, file+" (new)")
+		} else if recorded != checksum {
+			untrusted = append(untrusted, file+" (changed)")
+		}
+		checksums[file] = checksum
+	}
+
+	if len(untrusted) == 0 {
+		return true
+	}
+
+	fmt.Fprintln(context.GetStdout(), "The following files are new or have changed since they were last allowed:")
+	for _, file := range untrusted {
+		fmt.Fprintln(context.GetStdout(), "  "+file)
+	}
+
+	if config.general.trust == "prompt" && isTerminal(os.Stdin) {
+		fmt.Fprint(context.GetStdout(), "Do you trust them? [y/N] ")
+		// the output of a fan-out project is written line by line
+		if w, ok := context.GetStdout().(interface{ Flush() }); ok {
+			w.Flush()
+		}
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer == "y" || answer == "yes" {
+			return allowTrustedFiles(context, checksums) == nil
+		}
+		return false
+	}
+
+	fmt.Fprintln(context.GetStdout(), "Run `gm -g-allow` to trust them")
+	return false
+}
+
+// AllowProject trusts the project config and project local wrappers found from the current directory upwards
+func AllowProject(context Context) {
+	checksums := make(map[string]string)
+	for _, file := range findTrustCandidates(context, context.GetWorkingDir()) {
+		checksum, err := sha256File(file)
+		if err == nil {
+			checksums[file] = checksum
+		}
+	}
+
+	if len(checksums) == 0 {
+		fmt.Fprintln(context.GetStdout(), "Did not find a project config nor a project wrapper to allow")
+		context.Exit(-1)
+		return
+	}
+
+	if err := allowTrustedFiles(context, checksums); err != nil {
+		fmt.Fprintln(context.GetStdout(), err)
+		context.Exit(-1)
+		return
+	}
+
+	for _, file := range sortedKeys(checksums) {
+		fmt.Fprintln(context.GetStdout(), "Allowed "+file)
+	}
+}
+
+// DenyProject revokes trust from the directories holding the project config and project local wrappers
+// found from the current directory upwards
+func DenyProject(context Context) {
+	dirs := make(map[string]string)
+	for _, file := range findTrustCandidates(context, context.GetWorkingDir()) {
+		dirs[filepath.Dir(file)] = file
+	}
+
+	if len(dirs) == 0 {
+		dirs[context.GetWorkingDir()] = ""
+	}
+
+	for _, dir := range sortedKeys(dirs) {
+		key := trustKey(dir)
+		os.Remove(filepath.Join(resolveTrustDir(context), "allow", key))
+		if err := writeTrustFile(filepath.Join(resolveTrustDir(context), "deny", key), dir+"\n"); err != nil {
+			fmt.Fprintln(context.GetStdout(), err)
+			context.Exit(-1)
+			return
+		}
+		fmt.Fprintln(context.GetStdout(), "Denied "+dir)
+	}
+}
+
+// Files read by gradlew next to the script itself, they decide which Gradle distribution runs
+func resolveGradleWrapperFiles(wrapper string) []string {
+	dir := filepath.Join(filepath.Dir(wrapper), "gradle", "wrapper")
+	return []string{wrapper, filepath.Join(dir, "gradle-wrapper.jar"), filepath.Join(dir, "gradle-wrapper.properties")}
+}
+
+// Files read by mvnw next to the script itself, they decide which Maven distribution runs
+func resolveMavenWrapperFiles(wrapper string) []string {
+	dir := filepath.Join(filepath.Dir(wrapper), ".mvn", "wrapper")
+	return []string{wrapper, filepath.Join(dir, "maven-wrapper.jar"), filepath.Join(dir, "maven-wrapper.properties")}
+}
+
+// Finds project configs and project local wrappers from the given directory upwards.
+// The user config is skipped.
+func findTrustCandidates(context Context, dir string) []string {
+	names := []string{".gm.toml"}
+	names = append(names, resolveGradleWrapperFiles(resolveGradleWrapperExec(context))...)
+	names = append(names, resolveMavenWrapperFiles(resolveMavenWrapperExec(context))...)
+	names = append(names,
+		resolveJbangWrapperExec(context),
+		resolveSbtExec(context),
+		resolveMillExec(context),
+		resolveMillWrapperExec(context),
+		filepath.Join("tools", resolveBazelExec(context)),
+		resolveAmperWrapperExec(context))
+	if !context.IsWindows() {
+		names = append(names, "sbtx")
+	}
+	userConfig := filepath.Join(context.GetHomeDir(), ".gm.toml")
+
+	candidates := make([]string, 0)
+	for {
+		for _, name := range names {
+			path, _ := filepath.Abs(filepath.Join(dir, name))
+			if path != userConfig && isRegularFile(path) {
+				candidates = append(candidates, path)
+			}
+		}
+
+		parentdir, ok := resolveSearchParent(context, dir)
+		if !ok {
+			return candidates
+		}
+		dir = parentdir
+	}
+}
+
+// Records the given file checksums, merging them with those already recorded per directory
+func allowTrustedFiles(context Context, checksums map[string]string) error {
+	dirs := make(map[string]map[string]string)
+	for file, checksum := range checksums {
+		dir := filepath.Dir(file)
+		if dirs[dir] == nil {
+			dirs[dir] = readTrustedFiles(context, dir)
+		}
+		dirs[dir][file] = checksum
+	}
+
+	for dir, files := range dirs {
+		var b strings.Builder
+		b.WriteString("# " + dir + "\n")
+		for _, file := range sortedKeys(files) {
+			b.WriteString(files[file] + " " + file + "\n")
+		}
+
+		key := trustKey(dir)
+		os.Remove(filepath.Join(resolveTrustDir(context), "deny", key))
+		if err := writeTrustFile(filepath.Join(resolveTrustDir(context), "allow", key), b.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Reads the file checksums recorded for the given directory
+func readTrustedFiles(context Context, dir string) map[string]string {
+	files := make(map[string]string)
+
+	data, err := os.ReadFile(filepath.Join(resolveTrustDir(context), "allow", trustKey(dir)))
+	if err != nil {
+		return files
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, " ", 2)
+		if len(parts) == 2 {
+			files[parts[1]] = parts[0]
+		}
+	}
+
+	return files
+}
+
+// Checks if the given file has been allowed and has not changed since
+func isTrustedFile(context Context, config *Config, file string) bool {
+	if config.general.trust == "always" {
+		return true
+	}
+
+	dir := filepath.Dir(file)
+	if isTrustDenied(context, dir) {
+		return false
+	}
+
+	checksum, err := sha256File(file)
+	return err == nil && readTrustedFiles(context, dir)[file] == checksum
+}
+
+func isTrustDenied(context Context, dir string) bool {
+	return isRegularFile(filepath.Join(resolveTrustDir(context), "deny", trustKey(dir)))
+}
+
+func writeTrustFile(file string, content string) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(file, []byte(content), 0600)
+}
+
+// Trust records are stored per directory, named after the SHA-256 of the directory path
+func trustKey(dir string) string {
+	sum := sha256.Sum256([]byte(dir))
+	return hex.EncodeToString(sum[:])
+}
+
+// Resolves the trust store directory.
+// Linux/MacOS: $XDG_CONFIG_HOME/gm/trust, defaults to $HOME/.config/gm/trust
+// Windows: %APPDATA%\Gum\trust
+func resolveTrustDir(context Context) string {
+	if context.IsWindows() {
+		return filepath.Join(context.GetHomeDir(), "Gum", "trust")
+	}
+
+	configHome := context.GetEnv("XDG_CONFIG_HOME")
+	if len(configHome) == 0 {
+		configHome = filepath.Join(context.GetHomeDir(), ".config")
+	}
+	return filepath.Join(configHome, "gm", "trust")
+}
+
+// Checks if the given file is an interactive terminal, /dev/null is a character device as well
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(info, null)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,254 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTrustProject(t *testing.T) {
+	// given:
+	home := t.TempDir()
+	pwd := t.TempDir()
+	gradlew := filepath.Join(pwd, "gradlew")
+	os.WriteFile(gradlew, []byte("#!/bin/sh\n"), 0755)
+	os.WriteFile(filepath.Join(pwd, ".gm.toml"), []byte("[gradle]\nreplace = false\n"), 0644)
+
+	context := testContext{
+		quiet:      true,
+		workingDir: pwd,
+		homeDir:    home}
+
+	config := ReadConfig(context, pwd)
+	config.general.trust = "never"
+
+	// when:
+	trusted := trustProject(context, config, gradlew)
+
+	// then:
+	if trusted {
+		t.Error("new files: got trusted, want untrusted")
+	}
+	if config.projectFile != filepath.Join(pwd, ".gm.toml") {
+		t.Errorf("projectFile: got %s, want %s", config.projectFile, filepath.Join(pwd, ".gm.toml"))
+	}
+
+	// when:
+	AllowProject(context)
+
+	// then:
+	if !trustProject(context, config, gradlew) {
+		t.Error("allowed files: got untrusted, want trusted")
+	}
+
+	// when:
+	os.WriteFile(gradlew, []byte("#!/bin/sh\necho changed\n"), 0755)
+
+	// then:
+	if trustProject(context, config, gradlew) {
+		t.Error("changed files: got trusted, want untrusted")
+	}
+
+	// when:
+	AllowProject(context)
+	DenyProject(context)
+
+	// then:
+	if trustProject(context, config, gradlew) {
+		t.Error("denied files: got trusted, want untrusted")
+	}
+
+	// when:
+	config.general.trust = "always"
+
+	// then:
+	if !trustProject(context, config, gradlew) {
+		t.Error("always: got untrusted, want trusted")
+	}
+}
+
+func TestTrustIgnoresProjectSettings(t *testing.T) {
+	// given:
+	pwd := t.TempDir()
+	os.WriteFile(filepath.Join(pwd, ".gm.toml"), []byte("[general]\ntrust = \"always\"\n"), 0644)
+
+	context := testContext{
+		quiet:      true,
+		workingDir: pwd,
+		homeDir:    t.TempDir()}
+
+	// when:
+	config := ReadConfig(context, pwd)
+
+	// then:
+	if config.general.trust != "prompt" {
+		t.Errorf("trust: got %s, want prompt", config.general.trust)
+	}
+}
+
+func TestTrustWithholdsProjectExecutables(t *testing.T) {
+	// given:
+	pwd := t.TempDir()
+	os.WriteFile(filepath.Join(pwd, ".gm.toml"), []byte("[gradle]\nexecutable = \"/opt/gradle/bin/gradle\"\n[java]\nversion = \"17\"\n"), 0644)
+
+	context := testContext{
+		quiet:      true,
+		workingDir: pwd,
+		homeDir:    t.TempDir()}
+
+	// when:
+	config := ReadConfig(context, pwd)
+
+	// then:
+	if config.gradle.executable != "" {
+		t.Errorf("untrusted executable: got %s, want none", config.gradle.executable)
+	}
+	if config.java.version != "" {
+		t.Errorf("untrusted java version: got %s, want none", config.java.version)
+	}
+
+	// when:
+	AllowProject(context)
+
+	// then:
+	if !trustProject(context, config) {
+		t.Error("allowed config: got untrusted, want trusted")
+	}
+	if config.gradle.executable != "/opt/gradle/bin/gradle" {
+		t.Errorf("restored executable: got %s, want /opt/gradle/bin/gradle", config.gradle.executable)
+	}
+
+	// when:
+	config = ReadConfig(context, pwd)
+
+	// then:
+	if config.gradle.executable != "/opt/gradle/bin/gradle" {
+		t.Errorf("trusted executable: got %s, want /opt/gradle/bin/gradle", config.gradle.executable)
+	}
+	if config.java.version != "17" {
+		t.Errorf("trusted java version: got %s, want 17", config.java.version)
+	}
+}
+
+func TestTrustWrapperFiles(t *testing.T) {
+	// given:
+	home := t.TempDir()
+	pwd := t.TempDir()
+	os.MkdirAll(filepath.Join(pwd, "gradle", "wrapper"), 0755)
+	os.MkdirAll(filepath.Join(pwd, ".mvn", "wrapper"), 0755)
+	gradlew := filepath.Join(pwd, "gradlew")
+	mvnw := filepath.Join(pwd, "mvnw")
+	os.WriteFile(gradlew, []byte("#!/bin/sh\n"), 0755)
+	os.WriteFile(mvnw, []byte("#!/bin/sh\n"), 0755)
+	files := []string{
+		filepath.Join(pwd, "gradle", "wrapper", "gradle-wrapper.jar"),
+		filepath.Join(pwd, "gradle", "wrapper", "gradle-wrapper.properties"),
+		filepath.Join(pwd, ".mvn", "wrapper", "maven-wrapper.jar"),
+		filepath.Join(pwd, ".mvn", "wrapper", "maven-wrapper.properties")}
+
+	context := testContext{
+		quiet:      true,
+		workingDir: pwd,
+		homeDir:    home}
+
+	config := ReadConfig(context, pwd)
+	config.general.trust = "never"
+
+	for _, file := range files {
+		// when:
+		os.WriteFile(file, []byte("distributionUrl=https://example.com/dist.zip\n"), 0644)
+		AllowProject(context)
+
+		// then:
+		if !trustProject(context, config, resolveGradleWrapperFiles(gradlew)...) || !trustProject(context, config, resolveMavenWrapperFiles(mvnw)...) {
+			t.Errorf("%s allowed: got untrusted, want trusted", file)
+		}
+
+		// when:
+		os.WriteFile(file, []byte("distributionUrl=https://example.org/dist.zip\n"), 0644)
+
+		// then:
+		if trustProject(context, config, resolveGradleWrapperFiles(gradlew)...) && trustProject(context, config, resolveMavenWrapperFiles(mvnw)...) {
+			t.Errorf("%s changed: got trusted, want untrusted", file)
+		}
+	}
+}
+
+func TestTrustWritesToContext(t *testing.T) {
+	// given:
+	pwd := t.TempDir()
+	gradlew := filepath.Join(pwd, "gradlew")
+	os.WriteFile(gradlew, []byte("#!/bin/sh\n"), 0755)
+
+	out := &bytes.Buffer{}
+	context := testContext{
+		quiet:      true,
+		workingDir: pwd,
+		homeDir:    t.TempDir(),
+		stdout:     out}
+
+	config := ReadConfig(context, pwd)
+	config.general.trust = "never"
+
+	// when:
+	trustProject(context, config, gradlew)
+	AllowProject(context)
+	DenyProject(context)
+
+	// then:
+	for _, expected := range []string{
+		"The following files are new or have changed since they were last allowed:\n  " + gradlew + " (new)\n",
+		"Run `gm -g-allow` to trust them\n",
+		"Allowed " + gradlew + "\n",
+		"Denied " + pwd + "\n"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("output: got %q want %q", out.String(), expected)
+		}
+	}
+}
+
+func TestTrustCandidates(t *testing.T) {
+	// given:
+	pwd := t.TempDir()
+	os.MkdirAll(filepath.Join(pwd, "tools"), 0755)
+	for _, name := range []string{"sbtx", "millw", filepath.Join("tools", "bazel"), "amper"} {
+		os.WriteFile(filepath.Join(pwd, name), []byte("#!/bin/sh\n"), 0755)
+	}
+
+	context := testContext{
+		quiet:      true,
+		workingDir: pwd,
+		homeDir:    t.TempDir()}
+
+	// when:
+	candidates := findTrustCandidates(context, pwd)
+
+	// then:
+	found := make(map[string]bool)
+	for _, candidate := range candidates {
+		found[candidate] = true
+	}
+	for _, name := range []string{"sbtx", "millw", filepath.Join("tools", "bazel"), "amper"} {
+		if !found[filepath.Join(pwd, name)] {
+			t.Errorf("candidates: %s not found in %v", name, candidates)
+		}
+	}
+}
@@ -16,6 +16,8 @@
 
 package gum
 
+import "io"
+
 // Command defines an executable command (gradle/maven)
 type Command interface {
 	// Execute executes the given command
@@ -42,30 +44,42 @@ type Context interface {
 	// GetPaths gets the paths in $PATH
 	GetPaths() []string
 
+	// GetEnv gets the value of the given environment variable
+	GetEnv(key string) string
+
 	// FileExists checks if a file exists
 	FileExists(name string) bool
 
+	// GetStdout returns the writer receiving the standard output of executed tools
+	GetStdout() io.Writer
+
+	// GetStderr returns the writer receiving the standard error of executed tools
+	GetStderr() io.Writer
+
 	// Exit causes the current program to exit with the given status code.
 	Exit(code int)
 }
 
-// Theme defines a console theme for printing messages
+// Theme defines a console theme for printing messages to a given writer
 type Theme interface {
 	// PrintSection prints a section header such as [section]
-	PrintSection(section string)
+	PrintSection(out io.Writer, section string)
 
 	// PrintKeyValueBoolean prints a key/value pair as key = value
-	PrintKeyValueBoolean(key string, value bool)
+	PrintKeyValueBoolean(out io.Writer, key string, value bool)
 
 	// PrintKeyValueLiteral prints a key/value pair as key = "value"
-	PrintKeyValueLiteral(key string, value string)
+	PrintKeyValueLiteral(out io.Writer, key string, value string)
 
 	// PrintKeyValueArrayS prints a key/value pair as key = ["v1", "v2"]
-	PrintKeyValueArrayS(key string, value []string)
+	PrintKeyValueArrayS(out io.Writer, key string, value []string)
 
 	// PrintKeyValueArrayI prints a key/value pair as key = [i1, i2]
-	PrintKeyValueArrayI(key string, value [2]uint8)
+	PrintKeyValueArrayI(out io.Writer, key string, value [2]uint8)
 
 	// PrintMap prints a map with each entry as key = "value"
-	PrintMap(value map[string]string)
+	PrintMap(out io.Writer, value map[string]string)
+
+	// PrintTable prints rows aligned in columns below the given headers
+	PrintTable(out io.Writer, headers []string, rows [][]string)
 }
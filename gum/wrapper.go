@@ -0,0 +1,324 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"bufio"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+//go:embed gradle-wrapper-checksums.txt
+var gradleWrapperChecksums string
+
+// wrapperVerification collects the outcome of verifying a project local wrapper
+type wrapperVerification struct {
+	// verification mode, either "strict", "warn", or "off"
+	mode string
+	// facts gathered during verification, shown with -gd
+	details [][2]string
+	// problems found during verification
+	problems []string
+}
+
+func (v *wrapperVerification) detail(key string, value string) {
+	v.details = append(v.details, [2]string{key, value})
+}
+
+// Prints the facts gathered during verification, aligning keys to the given width
+func (v *wrapperVerification) debug(context Context, width int) {
+	if v.mode == "off" {
+		fmt.Fprintf(context.GetStdout(), "%-*s =  %s", width, "verify wrapper", v.mode)
+		fmt.Fprintln(context.GetStdout())
+		return
+	}
+	for _, detail := range v.details {
+		fmt.Fprintf(context.GetStdout(), "%-*s =  %s", width, detail[0], detail[1])
+		fmt.Fprintln(context.GetStdout())
+	}
+}
+
+func (v *wrapperVerification) problem(problem string) {
+	v.problems = append(v.problems, problem)
+}
+
+// Reports the problems found, returning false if the wrapper must not be executed
+func (v *wrapperVerification) report(context Context, config *Config, wrapper string) bool {
+	if v.mode == "off" || len(v.problems) == 0 {
+		return true
+	}
+
+	if v.mode == "strict" {
+		fmt.Fprintf(context.GetStdout(), "Refusing to execute %s:", wrapper)
+		fmt.Fprintln(context.GetStdout())
+	} else if !config.general.quiet {
+		fmt.Fprintf(context.GetStdout(), "WARNING: could not verify %s:", wrapper)
+		fmt.Fprintln(context.GetStdout())
+	}
+
+	if v.mode == "strict" || !config.general.quiet {
+		for _, problem := range v.problems {
+			fmt.Fprintln(context.GetStdout(), "  "+problem)
+		}
+		fmt.Fprintln(context.GetStdout())
+	}
+
+	return v.mode != "strict"
+}
+
+// Verifies the Gradle wrapper found at wrapperdir.
+// Checks the SHA-256 of gradle/wrapper/gradle-wrapper.jar against known-good checksums, and
+// distributionSha256Sum in gradle/wrapper/gradle-wrapper.properties against the cached distribution.
+func verifyGradleWrapper(context Context, config *Config, wrapperdir string) *wrapperVerification {
+	v := &wrapperVerification{mode: config.gradle.verifyWrapper}
+	if v.mode == "off" {
+		return v
+	}
+
+	jar := filepath.Join(wrapperdir, "gradle", "wrapper", "gradle-wrapper.jar")
+	checksum, err := sha256File(jar)
+	if err != nil {
+		v.detail("wrapper jar", "not found")
+		v.problem("Could not read " + jar)
+	} else {
+		checksums := readChecksums(config.gradle.wrapperChecksums)
+		_, known := checksums[checksum]
+		v.detail("wrapper jar sha256", checksum)
+		if len(checksums) == 0 {
+			v.detail("wrapper jar", "unverified, no known checksums")
+			v.problem("No known checksums to verify " + jar + " against. Refresh them with `make gradle-wrapper-checksums`" +
+				" when building Gum, or list them in the file set by wrapperChecksums in the [gradle] section")
+		} else if known {
+			v.detail("wrapper jar", "known")
+		} else {
+			v.detail("wrapper jar", "unknown")
+			v.problem(jar + " has an unknown checksum " + checksum)
+		}
+	}
+
+	properties := readProperties(filepath.Join(wrapperdir, "gradle", "wrapper", "gradle-wrapper.properties"))
+	url := properties["distributionUrl"]
+	sum := strings.ToLower(properties["distributionSha256Sum"])
+	v.detail("distribution url", url)
+	if len(sum) > 0 && len(url) > 0 {
+		gradleUserHome := context.GetEnv("GRADLE_USER_HOME")
+		if len(gradleUserHome) == 0 {
+			gradleUserHome = filepath.Join(context.GetHomeDir(), ".gradle")
+		}
+		verifyDistribution(v, filepath.Join(gradleUserHome, "wrapper", "dists"), url, sum, "distributionSha256Sum")
+	}
+
+	return v
+}
+
+// Hosts Maven wrappers may download from without a warning
+var mavenWrapperHosts = []string{"repo.maven.apache.org", "repo1.maven.org"}
+
+var mavenWrapperScriptVersionPattern = regexp.MustCompile(`Maven Wrapper startup (?:batch )?script, version (\S+)`)
+
+// Verifies the Maven wrapper found at wrapperdir.
+// Reads .mvn/wrapper/maven-wrapper.properties, checks the hosts it downloads from, distributionSha256Sum against
+// the distribution cached at ~/.m2/wrapper/dists, and wrapperSha256Sum against .mvn/wrapper/maven-wrapper.jar.
+func verifyMavenWrapper(context Context, config *Config, wrapperdir string) *wrapperVerification {
+	v := &wrapperVerification{mode: config.maven.verifyWrapper}
+	if v.mode == "off" {
+		return v
+	}
+
+	file := filepath.Join(wrapperdir, ".mvn", "wrapper", "maven-wrapper.properties")
+	if !context.FileExists(file) {
+		v.detail("wrapper properties", "not found")
+		v.problem("Could not read " + file)
+		return v
+	}
+
+	properties := readProperties(file)
+	version := properties["wrapperVersion"]
+	if len(version) == 0 {
+		version = readMavenWrapperScriptVersion(filepath.Join(wrapperdir, "mvnw"))
+	}
+	v.detail("wrapper version", version)
+
+	hosts := append(mavenWrapperHosts, config.maven.wrapperHosts...)
+	distributionUrl := properties["distributionUrl"]
+	v.detail("distribution url", distributionUrl)
+	if len(distributionUrl) == 0 {
+		v.problem(file + " does not define distributionUrl")
+	} else {
+		verifyWrapperUrl(v, "distributionUrl", distributionUrl, hosts)
+	}
+	if wrapperUrl := properties["wrapperUrl"]; len(wrapperUrl) > 0 {
+		v.detail("wrapper url", wrapperUrl)
+		verifyWrapperUrl(v, "wrapperUrl", wrapperUrl, hosts)
+	}
+
+	sum := strings.ToLower(properties["distributionSha256Sum"])
+	if len(sum) > 0 && len(distributionUrl) > 0 {
+		mavenUserHome := context.GetEnv("MAVEN_USER_HOME")
+		if len(mavenUserHome) == 0 {
+			mavenUserHome = filepath.Join(context.GetHomeDir(), ".m2")
+		}
+		verifyDistribution(v, filepath.Join(mavenUserHome, "wrapper", "dists"), distributionUrl, sum, "distributionSha256Sum")
+	}
+
+	sum = strings.ToLower(properties["wrapperSha256Sum"])
+	if len(sum) > 0 {
+		jar := filepath.Join(wrapperdir, ".mvn", "wrapper", "maven-wrapper.jar")
+		checksum, err := sha256File(jar)
+		if err != nil {
+			v.detail("wrapper jar sum", sum+" (not downloaded yet)")
+		} else if checksum != sum {
+			v.detail("wrapper jar sum", sum+" (mismatch)")
+			v.problem(jar + " has checksum " + checksum + " but wrapperSha256Sum is " + sum)
+		} else {
+			v.detail("wrapper jar sum", sum+" (verified)")
+		}
+	}
+
+	return v
+}
+
+// Checks the given url uses HTTPS and points at one of the given hosts
+func verifyWrapperUrl(v *wrapperVerification, property string, location string, hosts []string) {
+	u, err := url.Parse(location)
+	if err != nil {
+		v.problem(property + " is not a valid URL: " + location)
+		return
+	}
+
+	if u.Scheme != "https" {
+		v.problem(property + " does not use HTTPS: " + location)
+	}
+
+	for _, host := range hosts {
+		if strings.EqualFold(u.Hostname(), host) {
+			return
+		}
+	}
+	v.problem(property + " points at a host that is not allowed: " + u.Hostname())
+}
+
+// Reads the version from the header of a Maven wrapper script
+func readMavenWrapperScriptVersion(script string) string {
+	data, err := os.ReadFile(script)
+	if err != nil {
+		return ""
+	}
+
+	matches := mavenWrapperScriptVersionPattern.FindSubmatch(data)
+	if matches == nil {
+		return ""
+	}
+	return string(matches[1])
+}
+
+// Verifies the distribution downloaded from url, if found in the cache at distsdir, has the given checksum.
+// Wrappers store distributions at <distsdir>/<name>/<hash>/<name>.zip
+func verifyDistribution(v *wrapperVerification, distsdir string, url string, sum string, property string) {
+	zip := filepath.Base(url)
+	name := strings.TrimSuffix(zip, ".zip")
+	matches, _ := filepath.Glob(filepath.Join(distsdir, name, "*", zip))
+	if len(matches) == 0 {
+		v.detail("distribution sum", sum+" (not downloaded yet)")
+		return
+	}
+
+	for _, match := range matches {
+		checksum, err := sha256File(match)
+		if err == nil && checksum != sum {
+			v.detail("distribution sum", sum+" (mismatch)")
+			v.problem(match + " has checksum " + checksum + " but " + property + " is " + sum)
+			return
+		}
+	}
+	v.detail("distribution sum", sum+" (verified)")
+}
+
+// Reads known-good checksums shipped with Gum and those found in the given file (if any)
+func readChecksums(file string) map[string]bool {
+	checksums := make(map[string]bool)
+	parseChecksums(checksums, gradleWrapperChecksums)
+
+	if len(file) > 0 {
+		data, err := os.ReadFile(file)
+		if err == nil {
+			parseChecksums(checksums, string(data))
+		}
+	}
+
+	return checksums
+}
+
+func parseChecksums(checksums map[string]bool, content string) {
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		checksums[strings.ToLower(fields[0])] = true
+	}
+}
+
+// Computes the SHA-256 of the given file as a lowercase hex string
+func sha256File(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Reads a Java properties file. Only key=value and key:value lines are supported
+func readProperties(file string) map[string]string {
+	properties := make(map[string]string)
+
+	f, err := os.Open(file)
+	if err != nil {
+		return properties
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		i := strings.IndexAny(line, "=:")
+		if i < 0 {
+			continue
+		}
+		// escaped colons are common in URLs, i.e, https\://
+		properties[strings.TrimSpace(line[:i])] = strings.ReplaceAll(strings.TrimSpace(line[i+1:]), "\\:", ":")
+	}
+
+	return properties
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build release
+
+package gum
+
+import (
+	"testing"
+)
+
+// Run by `make build` once the checksums are fetched, gm must not ship without them
+func TestEmbeddedGradleWrapperChecksums(t *testing.T) {
+	// given:
+	checksums := make(map[string]bool)
+
+	// when:
+	parseChecksums(checksums, gradleWrapperChecksums)
+
+	// then:
+	if len(checksums) == 0 {
+		t.Error("gradle-wrapper-checksums.txt has no entries, run `make gradle-wrapper-checksums`")
+	}
+	for checksum := range checksums {
+		if len(checksum) != 64 {
+			t.Errorf("checksum: got %s want a SHA-256", checksum)
+		}
+	}
+}
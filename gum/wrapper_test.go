@@ -0,0 +1,236 @@
+// SPDX-License-Identifier: Apache-2.0
+//
+// Copyright 2020-2025 Andres Almiray.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gum
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGradleWrapperVerificationWithKnownChecksum(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "wrapper-verify"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		homeDir:    filepath.Join(pwd, "home"),
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "build"})
+	cmd := FindGradle(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	cmd.config.gradle.verifyWrapper = "strict"
+	cmd.config.gradle.wrapperChecksums = filepath.Join(pwd, "checksums.txt")
+	cmd.doConfigureGradle()
+
+	if cmd.wrapper == nil {
+		t.Error("Expected wrapper verification but got nil")
+		return
+	}
+	if len(cmd.wrapper.problems) != 0 {
+		t.Errorf("problems: got %v, want none", cmd.wrapper.problems)
+	}
+	if !cmd.wrapper.report(cmd.context, cmd.config, cmd.executable) {
+		t.Error("report: got refused, want accepted")
+	}
+
+	details := make(map[string]string)
+	for _, detail := range cmd.wrapper.details {
+		details[detail[0]] = detail[1]
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"WrapperJar", details["wrapper jar"], "known"},
+		{"DistributionUrl", details["distribution url"], "https://services.gradle.org/distributions/gradle-8.10.2-bin.zip"},
+		{"DistributionSha256Sum", details["distribution sum"][len(details["distribution sum"])-10:], "(verified)"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestGradleWrapperVerificationWithUnknownChecksum(t *testing.T) {
+	// given:
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "wrapper-verify"))
+	context := testContext{
+		workingDir: pwd,
+		homeDir:    filepath.Join(pwd, "home")}
+
+	for _, mode := range []string{"strict", "warn", "off"} {
+		config := newConfig()
+		config.setQuiet(true)
+		config.gradle.verifyWrapper = mode
+		config.gradle.wrapperChecksums = filepath.Join(pwd, "other-checksums.txt")
+
+		// when:
+		v := verifyGradleWrapper(context, config, pwd)
+
+		// then:
+		expected := mode != "strict"
+		if v.report(context, config, "gradlew") != expected {
+			t.Errorf("%s: got %t, want %t", mode, !expected, expected)
+		}
+	}
+}
+
+func TestWrapperDistributionMismatch(t *testing.T) {
+	// given:
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "wrapper-verify"))
+	dists := filepath.Join(pwd, "home", ".gradle", "wrapper", "dists")
+	v := &wrapperVerification{mode: "strict"}
+
+	// when:
+	verifyDistribution(v, dists, "https://services.gradle.org/distributions/gradle-8.10.2-bin.zip", "0000", "distributionSha256Sum")
+
+	// then:
+	if len(v.problems) != 1 {
+		t.Errorf("problems: got %d, want 1", len(v.problems))
+	}
+}
+
+func TestMavenWrapperVerification(t *testing.T) {
+	// given:
+	bin, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "bin"))
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "wrapper-verify"))
+
+	context := testContext{
+		quiet:      true,
+		explicit:   true,
+		windows:    false,
+		workingDir: pwd,
+		homeDir:    filepath.Join(pwd, "home"),
+		paths:      []string{bin}}
+
+	// when:
+	args := ParseArgs([]string{"-gq", "verify"})
+	cmd := FindMaven(context, &args)
+
+	// then:
+	if cmd == nil {
+		t.Error("Expected a command but got nil")
+		return
+	}
+
+	cmd.config.maven.verifyWrapper = "strict"
+	cmd.doConfigureMaven()
+
+	if cmd.wrapper == nil {
+		t.Error("Expected wrapper verification but got nil")
+		return
+	}
+	if len(cmd.wrapper.problems) != 0 {
+		t.Errorf("problems: got %v, want none", cmd.wrapper.problems)
+	}
+
+	details := make(map[string]string)
+	for _, detail := range cmd.wrapper.details {
+		details[detail[0]] = detail[1]
+	}
+
+	var checks = []struct {
+		title, actual, expected string
+	}{
+		{"WrapperVersion", details["wrapper version"], "3.3.2"},
+		{"DistributionUrl", details["distribution url"], "https://repo.maven.apache.org/maven2/org/apache/maven/apache-maven/3.9.9/apache-maven-3.9.9-bin.zip"},
+		{"DistributionSha256Sum", details["distribution sum"][len(details["distribution sum"])-10:], "(verified)"},
+		{"WrapperSha256Sum", details["wrapper jar sum"][len(details["wrapper jar sum"])-10:], "(verified)"},
+	}
+
+	for _, check := range checks {
+		if check.actual != check.expected {
+			t.Errorf("%s: got %s, want %s", check.title, check.actual, check.expected)
+		}
+	}
+}
+
+func TestMavenWrapperVerificationWithUntrustedHost(t *testing.T) {
+	// given:
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "maven", "wrapper-untrusted"))
+	context := testContext{
+		workingDir: pwd,
+		homeDir:    filepath.Join(pwd, "home")}
+
+	var checks = []struct {
+		hosts    []string
+		problems int
+	}{
+		{nil, 2},
+		{[]string{"mirror.example.com"}, 1},
+	}
+
+	for _, check := range checks {
+		config := newConfig()
+		config.setQuiet(true)
+		config.maven.verifyWrapper = "strict"
+		config.maven.wrapperHosts = check.hosts
+
+		// when:
+		v := verifyMavenWrapper(context, config, pwd)
+
+		// then:
+		if len(v.problems) != check.problems {
+			t.Errorf("problems: got %v, want %d", v.problems, check.problems)
+		}
+		if v.report(context, config, "mvnw") {
+			t.Error("report: got accepted, want refused")
+		}
+	}
+}
+
+func TestGradleWrapperVerificationWithoutChecksums(t *testing.T) {
+	if len(readChecksums("")) > 0 {
+		t.Skip("known checksums are shipped")
+	}
+
+	// given:
+	pwd, _ := filepath.Abs(filepath.Join("..", "tests", "gradle", "wrapper-verify"))
+	context := testContext{
+		workingDir: pwd,
+		homeDir:    filepath.Join(pwd, "home")}
+
+	config := newConfig()
+	config.setQuiet(true)
+	config.gradle.verifyWrapper = "warn"
+	config.gradle.wrapperChecksums = filepath.Join(pwd, "missing-checksums.txt")
+
+	// when:
+	v := verifyGradleWrapper(context, config, pwd)
+
+	// then:
+	if len(v.problems) != 1 {
+		t.Errorf("problems: got %v, want 1", v.problems)
+	}
+	if !v.report(context, config, "gradlew") {
+		t.Error("report: got refused, want accepted")
+	}
+}
@@ -0,0 +1,10 @@
+rootProject.name = "multi-project"
+
+// include("ignored")
+include(
+    ":libs:core",
+    ":api"
+)
+includeBuild("build-logic")
+
+project(":api").projectDir = file("modules/api")
@@ -0,0 +1,9 @@
+///usr/bin/env jbang "$0" "$@" ; exit $?
+//DEPS info.picocli:picocli:4.7.6
+//JAVA_OPTIONS -Xmx256m -Dgreeting=hello
+
+class hello {
+    public static void main(String... args) {
+        System.out.println(System.getProperty("greeting"));
+    }
+}